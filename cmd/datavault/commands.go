@@ -0,0 +1,308 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/datavault/dvmodel"
+	"github.com/guinso/rdbmstool"
+)
+
+func parseModelFlag(modelFile string) (*definition.DataVaultDefinition, error) {
+	if modelFile == "" {
+		return nil, errors.New("model file is required, set -model flag")
+	}
+
+	return dvmodel.ParseModelFile(modelFile)
+}
+
+//runApply create entities of model file which are not found in database;
+//existing data table is never altered or dropped
+func runApply(args []string, stdout io.Writer) error {
+	flagSet := newFlagSet("apply")
+	setting := addConnectionFlags(flagSet)
+	modelFile := flagSet.String("model", "", "model file (YAML or JSON)")
+	dryRun := flagSet.Bool("dry-run", false, "print SQL statements without execute them")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	dvDef, modelErr := parseModelFlag(*modelFile)
+	if modelErr != nil {
+		return modelErr
+	}
+
+	dv, dvErr := setting.connect()
+	if dvErr != nil {
+		return dvErr
+	}
+	defer dv.Db.Close()
+
+	diff, diffErr := dvmeta.DiffDataVault(dv.MetaReader, dv.Db, dvDef)
+	if diffErr != nil {
+		return diffErr
+	}
+	printWarnings(stdout, diff.Warnings)
+
+	plan, planErr := diff.GeneratePlan(dv.Dialect)
+	if planErr != nil {
+		return planErr
+	}
+
+	if *dryRun {
+		printStatements(stdout, plan)
+		return nil
+	}
+
+	transaction, beginErr := dv.Db.Begin()
+	if beginErr != nil {
+		return beginErr
+	}
+
+	for _, sql := range plan {
+		for _, statement := range splitStatements(sql) {
+			if _, execErr := transaction.Exec(statement); execErr != nil {
+				transaction.Rollback()
+				return fmt.Errorf("%s\n%s", execErr.Error(), statement)
+			}
+		}
+	}
+
+	if commitErr := transaction.Commit(); commitErr != nil {
+		return commitErr
+	}
+
+	fmt.Fprintf(stdout, "%d entity(s) created\n", len(plan))
+
+	return nil
+}
+
+//runList list hubs, links and satelites found in database
+func runList(args []string, stdout io.Writer) error {
+	flagSet := newFlagSet("list")
+	setting := addConnectionFlags(flagSet)
+	entityType := flagSet.String("type", "", "list only hub, link or satelite")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	dv, dvErr := setting.connect()
+	if dvErr != nil {
+		return dvErr
+	}
+	defer dv.Db.Close()
+
+	listers := []struct {
+		entityType definition.EntityType
+		list       func(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error)
+	}{
+		{definition.HUB, dv.MetaReader.GetAllHubs},
+		{definition.LINK, dv.MetaReader.GetAllLinks},
+		{definition.SATELITE, dv.MetaReader.GetAllSatelites}}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "TYPE\tNAME\tREVISION")
+	found := false
+	var warnings []string
+	for _, lister := range listers {
+		if *entityType != "" && !strings.EqualFold(*entityType, lister.entityType.String()) {
+			continue
+		}
+		found = true
+
+		//data table which name is not valid data vault table name is warned instead
+		infos, listErr := lister.list(dv.Db)
+		if readErr, isReadErr := listErr.(*dvmeta.ReadError); isReadErr {
+			warnings = append(warnings, readErr.Problems...)
+		} else if listErr != nil {
+			return listErr
+		}
+
+		for _, info := range infos {
+			fmt.Fprintf(writer, "%s\t%s\t%d\n", info.Type, info.Name, info.Revision)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("unknown entity type %s, expect hub, link or satelite", *entityType)
+	}
+
+	if flushErr := writer.Flush(); flushErr != nil {
+		return flushErr
+	}
+	printWarnings(stdout, warnings)
+
+	return nil
+}
+
+//printWarnings print data table(s) which are skipped as they are not data vault entity
+func printWarnings(stdout io.Writer, warnings []string) {
+	for _, warning := range warnings {
+		fmt.Fprintf(stdout, "warning: skip %s\n", warning)
+	}
+}
+
+//runDescribe print hub definition with its satelites and links
+func runDescribe(args []string, stdout io.Writer) error {
+	flagSet := newFlagSet("describe")
+	setting := addConnectionFlags(flagSet)
+	hubName := flagSet.String("hub", "", "hub name")
+	revision := flagSet.Int("revision", 0, "hub revision")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if *hubName == "" {
+		return errors.New("hub name is required, set -hub flag")
+	}
+
+	dv, dvErr := setting.connect()
+	if dvErr != nil {
+		return dvErr
+	}
+	defer dv.Db.Close()
+
+	hubDef, hubErr := dv.MetaReader.GetHubDefinition(*hubName, *revision, dv.Db)
+	if hubErr != nil {
+		return hubErr
+	}
+
+	relationship, relErr := dv.MetaReader.GetRelationship(dv.Db, *hubName, *revision)
+	if relErr != nil {
+		return relErr
+	}
+
+	fmt.Fprintf(stdout, "hub %s revision %d (%s)\n", hubDef.Name, hubDef.Revision, hubDef.GetDbTableName())
+	for _, bk := range hubDef.BusinessKeys {
+		col, colErr := bk.GetBusinessKeyColumn()
+		if colErr != nil {
+			return colErr
+		}
+		fmt.Fprintf(stdout, "  business key %s %s\n", bk.Name, formatColumnType(col))
+	}
+
+	for _, satDef := range relationship.Satelites {
+		printSatelite(stdout, "", &satDef)
+	}
+
+	for _, link := range relationship.Links {
+		fmt.Fprintf(stdout, "link %s revision %d (%s)\n",
+			link.Definition.Name, link.Definition.Revision, link.Definition.GetDbTableName())
+		for _, hubRef := range link.Definition.HubReferences {
+			if hubRef.Role != "" {
+				fmt.Fprintf(stdout, "  hub %s revision %d as %s\n", hubRef.HubName, hubRef.Revision, hubRef.Role)
+			} else {
+				fmt.Fprintf(stdout, "  hub %s revision %d\n", hubRef.HubName, hubRef.Revision)
+			}
+		}
+
+		for _, satDef := range link.LinkSatelites {
+			printSatelite(stdout, "  ", &satDef)
+		}
+	}
+
+	return nil
+}
+
+func printSatelite(stdout io.Writer, indent string, satDef *definition.SateliteDefinition) {
+	fmt.Fprintf(stdout, "%ssatelite %s revision %d (%s)\n", indent,
+		satDef.Name, satDef.Revision, satDef.GetDbTableName())
+	for _, attr := range satDef.Attributes {
+		fmt.Fprintf(stdout, "%s  attribute %s %s\n", indent, attr.Name,
+			formatColumnType(attr.GetAttributeColumn()))
+	}
+}
+
+func formatColumnType(col rdbmstool.ColumnDefinition) string {
+	result := col.DataType.String()
+	if col.DataType == rdbmstool.CHAR || col.DataType == rdbmstool.VARCHAR {
+		result = fmt.Sprintf("%s(%d)", result, col.Length)
+	} else if col.DataType == rdbmstool.DECIMAL {
+		result = fmt.Sprintf("%s(%d,%d)", result, col.Length, col.DecimalPrecision)
+	}
+
+	if col.IsNullable {
+		return result + " NULL"
+	}
+
+	return result + " NOT NULL"
+}
+
+//runDiff print differences between model file and database
+func runDiff(args []string, stdout io.Writer) error {
+	flagSet := newFlagSet("diff")
+	setting := addConnectionFlags(flagSet)
+	modelFile := flagSet.String("model", "", "model file (YAML or JSON)")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	dvDef, modelErr := parseModelFlag(*modelFile)
+	if modelErr != nil {
+		return modelErr
+	}
+
+	dv, dvErr := setting.connect()
+	if dvErr != nil {
+		return dvErr
+	}
+	defer dv.Db.Close()
+
+	diff, diffErr := dvmeta.DiffDataVault(dv.MetaReader, dv.Db, dvDef)
+	if diffErr != nil {
+		return diffErr
+	}
+	printWarnings(stdout, diff.Warnings)
+
+	if len(diff.Differences) == 0 {
+		fmt.Fprintln(stdout, "database matches model")
+		return nil
+	}
+
+	for _, difference := range diff.Differences {
+		fmt.Fprintf(stdout, "%s: %s\n", difference.Type, difference.String())
+	}
+
+	return nil
+}
+
+//runDDL print SQL statements to create all entities of model file; no database connection is needed
+func runDDL(args []string, stdout io.Writer) error {
+	flagSet := newFlagSet("ddl")
+	setting := connectionSetting{}
+	addDriverFlag(flagSet, &setting)
+	modelFile := flagSet.String("model", "", "model file (YAML or JSON)")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	sqlDialect, dialectErr := setting.getDialect()
+	if dialectErr != nil {
+		return dialectErr
+	}
+
+	dvDef, modelErr := parseModelFlag(*modelFile)
+	if modelErr != nil {
+		return modelErr
+	}
+
+	sqls, sqlErr := dvDef.GenerateDialectSQL(sqlDialect)
+	if sqlErr != nil {
+		return sqlErr
+	}
+
+	printStatements(stdout, sqls)
+
+	return nil
+}
+
+func printStatements(stdout io.Writer, sqls []string) {
+	for _, sql := range sqls {
+		fmt.Fprintf(stdout, "%s;\n\n", sql)
+	}
+}
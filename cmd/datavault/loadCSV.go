@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/datavault/record"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//runLoadCSV load each CSV row into hub and its satelite; CSV header must contain
+//hub business keys and satelite attributes (name in CamelCase or snake_case)
+func runLoadCSV(args []string, stdout io.Writer) error {
+	flagSet := newFlagSet("load-csv")
+	setting := addConnectionFlags(flagSet)
+	satName := flagSet.String("satelite", "", "satelite name")
+	revision := flagSet.Int("revision", 0, "satelite revision")
+	csvFile := flagSet.String("file", "", "CSV file with header row")
+	recordSource := flagSet.String("source", "", "record source, CSV file name if not set")
+	loadDateText := flagSet.String("load-date", "", "load date (YYYY-MM-DD HH:MM:SS), current time if not set")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	if *satName == "" || *csvFile == "" {
+		return errors.New("satelite name and CSV file are required, set -satelite and -file flags")
+	}
+
+	loadDate := time.Now().UTC().Truncate(time.Second)
+	if *loadDateText != "" {
+		parsed, parseErr := time.Parse("2006-01-02 15:04:05", *loadDateText)
+		if parseErr != nil {
+			return fmt.Errorf("invalid load date %s: %s", *loadDateText, parseErr.Error())
+		}
+		loadDate = parsed
+	}
+
+	if *recordSource == "" {
+		*recordSource = filepath.Base(*csvFile)
+	}
+
+	dv, dvErr := setting.connect()
+	if dvErr != nil {
+		return dvErr
+	}
+	defer dv.Db.Close()
+
+	satDef, satErr := dv.MetaReader.GetSateliteDefinition(*satName, *revision, dv.Db)
+	if satErr != nil {
+		return satErr
+	}
+
+	if satDef.HubReference == nil {
+		return fmt.Errorf("satelite %s is not attached to hub, only hub satelite is supported", *satName)
+	}
+
+	hubDef, hubErr := dv.MetaReader.GetHubDefinition(satDef.HubReference.HubName,
+		satDef.HubReference.Revision, dv.Db)
+	if hubErr != nil {
+		return hubErr
+	}
+
+	//hash key is computed by hash algorithm which hash key column is sized for
+	dv.Hasher = hashkey.CreateHasher()
+	dv.Hasher.Algorithm = hubDef.HashAlgorithm
+
+	file, openErr := os.Open(*csvFile)
+	if openErr != nil {
+		return openErr
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, headerErr := reader.Read()
+	if headerErr != nil {
+		return fmt.Errorf("fail to read CSV header: %s", headerErr.Error())
+	}
+
+	columns := make(map[string]int)
+	for index, name := range header {
+		columns[stringtool.ToSnakeCase(strings.TrimSpace(name))] = index
+	}
+
+	for _, bk := range hubDef.BusinessKeys {
+		if _, exists := columns[stringtool.ToSnakeCase(bk.Name)]; !exists {
+			return fmt.Errorf("business key %s not found in CSV header", bk.Name)
+		}
+	}
+
+	rowCount := 0
+	for {
+		row, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		} else if readErr != nil {
+			return readErr
+		}
+		rowCount++
+
+		dvRecord, recordErr := createCSVRecord(hubDef, satDef, columns, row, *recordSource, loadDate)
+		if recordErr != nil {
+			return fmt.Errorf("row %d: %s", rowCount, recordErr.Error())
+		}
+
+		if insertErr := dv.InsertRecord(dvRecord); insertErr != nil {
+			return fmt.Errorf("row %d: %s", rowCount, insertErr.Error())
+		}
+	}
+
+	fmt.Fprintf(stdout, "%d row(s) loaded into %s\n", rowCount, satDef.GetDbTableName())
+
+	return nil
+}
+
+//createCSVRecord convert CSV row into hub and satelite insert record;
+//existing hub is skipped and unchanged satelite row is not inserted again
+func createCSVRecord(hubDef *definition.HubDefinition, satDef *definition.SateliteDefinition,
+	columns map[string]int, row []string, recordSource string, loadDate time.Time) (*record.DvInsertRecord, error) {
+
+	businessKeys := []record.HubBusinessKeyInsertRecord{}
+	for _, bk := range hubDef.BusinessKeys {
+		value := strings.TrimSpace(row[columns[stringtool.ToSnakeCase(bk.Name)]])
+		if value == "" {
+			return nil, fmt.Errorf("business key %s is empty", bk.Name)
+		}
+
+		businessKeys = append(businessKeys, record.HubBusinessKeyInsertRecord{
+			BusinessKey: bk.Name, BusinessValue: value})
+	}
+
+	attributes := []record.SateliteAttrInsertRecord{}
+	for index := range satDef.Attributes {
+		attr := &satDef.Attributes[index]
+		var value interface{}
+		if colIndex, exists := columns[stringtool.ToSnakeCase(attr.Name)]; exists {
+			parsed, parseErr := parseCSVValue(row[colIndex], attr)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			value = parsed
+		}
+
+		if value == nil && !attr.IsNullable {
+			return nil, fmt.Errorf("attribute %s is required", attr.Name)
+		}
+
+		attributes = append(attributes, record.SateliteAttrInsertRecord{
+			AttributeName: attr.Name, Value: value, Meta: attr})
+	}
+
+	return &record.DvInsertRecord{
+		SkipExistingKeys:       true,
+		SkipUnchangedSatelites: true,
+		Hubs: []record.HubInsertRecord{
+			record.HubInsertRecord{
+				HubName:         hubDef.Name,
+				HubRevision:     hubDef.Revision,
+				RecordSource:    recordSource,
+				LoadDate:        loadDate,
+				BusinessKeyVues: businessKeys}},
+		Satelites: []record.SateliteInsertRecord{
+			record.SateliteInsertRecord{
+				SateliteName:         satDef.Name,
+				Revision:             satDef.Revision,
+				HubName:              hubDef.Name,
+				HubBusinessKeyValues: businessKeys,
+				RecordSource:         recordSource,
+				LoadDate:             loadDate,
+				Attributes:           attributes,
+				MultiActiveKeys:      satDef.MultiActiveKeys,
+				HasHashDiff:          satDef.HasHashDiff}}}, nil
+}
+
+//parseCSVValue convert CSV text into value of attribute datatype; empty text is null
+func parseCSVValue(text string, attr *definition.SateliteAttributeDefinition) (interface{}, error) {
+	if attr.DataType != rdbmstool.CHAR && attr.DataType != rdbmstool.VARCHAR &&
+		attr.DataType != rdbmstool.TEXT {
+		text = strings.TrimSpace(text)
+	}
+
+	if text == "" {
+		return nil, nil
+	}
+
+	var value interface{}
+	var err error
+	switch attr.DataType {
+	case rdbmstool.INTEGER:
+		value, err = strconv.Atoi(text)
+	case rdbmstool.DECIMAL, rdbmstool.FLOAT:
+		value, err = strconv.ParseFloat(text, 64)
+	case rdbmstool.BOOLEAN:
+		value, err = strconv.ParseBool(text)
+	case rdbmstool.DATE:
+		value, err = time.Parse("2006-01-02", text)
+	case rdbmstool.DATETIME:
+		value, err = time.Parse("2006-01-02 15:04:05", text)
+	default:
+		value = text
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("attribute %s: %s", attr.Name, err.Error())
+	}
+
+	return value, nil
+}
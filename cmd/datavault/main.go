@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/guinso/datavault"
+	"github.com/guinso/datavault/dialect"
+)
+
+//command is a sub command of datavault tool
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	command{"apply", "create missing hubs, links and satelites of model file", runApply},
+	command{"list", "list hubs, links and satelites in database", runList},
+	command{"describe", "describe hub with its satelites and links", runDescribe},
+	command{"diff", "compare model file with database", runDiff},
+	command{"ddl", "print SQL statements to create model file", runDDL},
+	command{"load-csv", "load CSV file into hub satelite", runLoadCSV}}
+
+//datavault is command line tool to administrate data vault database;
+//connection setting is read from flags, or DATAVAULT_* environment variables
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return nil
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout)
+		}
+	}
+
+	printUsage(os.Stderr)
+	return fmt.Errorf("unknown command %s", args[0])
+}
+
+func printUsage(writer io.Writer) {
+	fmt.Fprintln(writer, "usage: datavault <command> [flags]")
+	fmt.Fprintln(writer, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(writer, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(writer, "\nrun 'datavault <command> -h' for flags of each command")
+}
+
+//connectionSetting is database connection setting; default value is read from environment variable
+type connectionSetting struct {
+	driver   string
+	host     string
+	port     int
+	user     string
+	password string
+	dbName   string
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("datavault "+name, flag.ContinueOnError)
+}
+
+func addDriverFlag(flagSet *flag.FlagSet, setting *connectionSetting) {
+	flagSet.StringVar(&setting.driver, "driver", getEnv("DATAVAULT_DRIVER", "mysql"),
+		"database vendor: mysql, postgres or sqlite (env DATAVAULT_DRIVER)")
+}
+
+func addConnectionFlags(flagSet *flag.FlagSet) *connectionSetting {
+	setting := connectionSetting{}
+	addDriverFlag(flagSet, &setting)
+	flagSet.StringVar(&setting.host, "host", getEnv("DATAVAULT_HOST", "localhost"),
+		"database address (env DATAVAULT_HOST)")
+	flagSet.IntVar(&setting.port, "port", getEnvInt("DATAVAULT_PORT", 0),
+		"database port, vendor's default port if 0 (env DATAVAULT_PORT)")
+	flagSet.StringVar(&setting.user, "user", getEnv("DATAVAULT_USER", ""),
+		"database username (env DATAVAULT_USER)")
+	flagSet.StringVar(&setting.password, "password", getEnv("DATAVAULT_PASSWORD", ""),
+		"database password (env DATAVAULT_PASSWORD)")
+	flagSet.StringVar(&setting.dbName, "db", getEnv("DATAVAULT_DB", ""),
+		"database name, or database file path of sqlite (env DATAVAULT_DB)")
+
+	return &setting
+}
+
+func (setting *connectionSetting) getDialect() (dialect.Dialect, error) {
+	return dialect.GetDialect(setting.driver)
+}
+
+func (setting *connectionSetting) connect() (*datavault.DataVault, error) {
+	sqlDialect, dialectErr := setting.getDialect()
+	if dialectErr != nil {
+		return nil, dialectErr
+	}
+
+	if setting.dbName == "" {
+		return nil, errors.New("database name is required, set -db flag or DATAVAULT_DB")
+	}
+
+	if sqlDialect.Name() == dialect.SQLITE.Name() {
+		return datavault.CreateSQLiteDV(setting.dbName)
+	}
+
+	port := setting.port
+	if port == 0 && sqlDialect.Name() == dialect.POSTGRES.Name() {
+		port = 5432
+	} else if port == 0 {
+		port = 3306
+	}
+
+	return datavault.CreateDialectDV(sqlDialect, setting.host, setting.user, setting.password,
+		setting.dbName, port)
+}
+
+func getEnv(name string, defaultValue string) string {
+	if value, exists := os.LookupEnv(name); exists {
+		return value
+	}
+
+	return defaultValue
+}
+
+func getEnvInt(name string, defaultValue int) int {
+	if value, err := strconv.Atoi(getEnv(name, "")); err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+//splitStatements split generated SQL (create table followed by create index) into
+//individual statements, as not every database driver run multiple statements at once
+func splitStatements(sql string) []string {
+	var result []string
+	for _, statement := range strings.Split(sql, ";\n") {
+		if strings.TrimSpace(statement) != "" {
+			result = append(result, statement)
+		}
+	}
+
+	return result
+}
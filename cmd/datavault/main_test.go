@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	//explicitly include GO sqlite library
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const testModel = `hubs:
+  - name: Customer
+    businessKeys:
+      - name: Email
+        dataType: VARCHAR
+        length: 255
+satelites:
+  - name: Customer
+    hubReference:
+      hubName: Customer
+    hasHashDiff: true
+    attributes:
+      - name: FullName
+        dataType: VARCHAR
+        length: 100
+      - name: Age
+        dataType: INTEGER
+        isNullable: true
+`
+
+func runTestCommand(t *testing.T, args ...string) string {
+	var stdout bytes.Buffer
+	if err := run(args, &stdout); err != nil {
+		t.Fatalf("datavault %s: %s", strings.Join(args, " "), err.Error())
+	}
+
+	return stdout.String()
+}
+
+func TestCommands(t *testing.T) {
+	dir, dirErr := ioutil.TempDir("", "datavault")
+	if dirErr != nil {
+		t.Fatal(dirErr.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	dbFile := filepath.Join(dir, "vault.db")
+	modelFile := filepath.Join(dir, "model.yaml")
+	csvFile := filepath.Join(dir, "customer.csv")
+	if err := ioutil.WriteFile(modelFile, []byte(testModel), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := ioutil.WriteFile(csvFile, []byte("email,full_name,age\n"+
+		"ali@example.com,Ali,30\nbob@example.com,Bob,\n"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	conn := []string{"-driver", "sqlite", "-db", dbFile}
+
+	if ddl := runTestCommand(t, "ddl", "-driver", "postgres", "-model", modelFile); !strings.Contains(ddl,
+		"CREATE TABLE \"sat_customer_rev0\"") {
+		t.Errorf("Expect create satelite statement, given %s instead", ddl)
+	}
+
+	if out := runTestCommand(t, append([]string{"apply", "-model", modelFile}, conn...)...); !strings.Contains(
+		out, "2 entity(s) created") {
+		t.Errorf("Expect 2 entities are created, given %s instead", out)
+	}
+
+	if out := runTestCommand(t, append([]string{"apply", "-model", modelFile}, conn...)...); !strings.Contains(
+		out, "0 entity(s) created") {
+		t.Errorf("Expect apply again create nothing, given %s instead", out)
+	}
+
+	if out := runTestCommand(t, append([]string{"diff", "-model", modelFile}, conn...)...); !strings.Contains(
+		out, "database matches model") {
+		t.Errorf("Expect database matches model, given %s instead", out)
+	}
+
+	if out := runTestCommand(t, append([]string{"list"}, conn...)...); !strings.Contains(out, "hub") ||
+		!strings.Contains(out, "Customer") {
+		t.Errorf("Expect customer hub is listed, given %s instead", out)
+	}
+
+	stray, strayErr := sql.Open("sqlite3", dbFile)
+	if strayErr != nil {
+		t.Fatal(strayErr.Error())
+	}
+	_, strayErr = stray.Exec("CREATE TABLE hub_backup (x TEXT)")
+	stray.Close()
+	if strayErr != nil {
+		t.Fatal(strayErr.Error())
+	}
+
+	for _, args := range [][]string{[]string{"list"}, []string{"diff", "-model", modelFile},
+		[]string{"apply", "-model", modelFile}} {
+		if out := runTestCommand(t, append(args, conn...)...); !strings.Contains(
+			out, "warning: skip data table hub_backup") || strings.Contains(out, "extra entity") {
+			t.Errorf("Expect %s warn stray table hub_backup, given %s instead", args[0], out)
+		}
+	}
+
+	if out := runTestCommand(t, append([]string{"describe", "-hub", "Customer"}, conn...)...); !strings.Contains(
+		out, "business key Email VARCHAR(255) NOT NULL") || !strings.Contains(out, "attribute Age INTEGER NULL") {
+		t.Errorf("Expect hub is described, given %s instead", out)
+	}
+
+	for _, loadDate := range []string{"2017-08-01 00:00:00", "2017-08-02 00:00:00"} {
+		if out := runTestCommand(t, append([]string{"load-csv", "-satelite", "Customer", "-file", csvFile,
+			"-load-date", loadDate}, conn...)...); !strings.Contains(out, "2 row(s) loaded") {
+			t.Errorf("Expect 2 rows are loaded, given %s instead", out)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sat_customer_rev0").Scan(&count); err != nil || count != 2 {
+		t.Errorf("Expect unchanged rows are loaded once, given %d (%v) instead", count, err)
+	}
+}
+
+func TestLoadCSVHashAlgorithm(t *testing.T) {
+	dir, dirErr := ioutil.TempDir("", "datavault")
+	if dirErr != nil {
+		t.Fatal(dirErr.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	dbFile := filepath.Join(dir, "vault.db")
+	modelFile := filepath.Join(dir, "model.yaml")
+	csvFile := filepath.Join(dir, "customer.csv")
+	if err := ioutil.WriteFile(modelFile, []byte("hashAlgorithm: sha256\n"+testModel), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := ioutil.WriteFile(csvFile, []byte("email,full_name,age\nali@example.com,Ali,30\n"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	conn := []string{"-driver", "sqlite", "-db", dbFile}
+	runTestCommand(t, append([]string{"apply", "-model", modelFile}, conn...)...)
+	runTestCommand(t, append([]string{"load-csv", "-satelite", "Customer", "-file", csvFile}, conn...)...)
+
+	db, err := sql.Open("sqlite3", dbFile)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer db.Close()
+
+	var hashKey string
+	if err := db.QueryRow("SELECT customer_hash_key FROM hub_customer_rev0").Scan(&hashKey); err != nil ||
+		len(hashKey) != 64 {
+		t.Errorf("Expect SHA-256 hash key is loaded, given %s (%v) instead", hashKey, err)
+	}
+}
@@ -4,29 +4,87 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/guinso/datavault/dialect"
 	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/datavault/hashkey"
 	mysqlMeta "github.com/guinso/datavault/dvmeta/mysql"
+	postgresMeta "github.com/guinso/datavault/dvmeta/postgres"
+	sqliteMeta "github.com/guinso/datavault/dvmeta/sqlite"
 	"github.com/guinso/datavault/record"
 
 	//explicitly include GO mysql library
 	_ "github.com/go-sql-driver/mysql"
+	//explicitly include GO postgres library
+	_ "github.com/lib/pq"
+	//explicitly include GO sqlite library
+	_ "github.com/mattn/go-sqlite3"
 )
 
-//DataVault handler of data vault
+//DataVault handler of data vault;
+//Hasher is optional, default hasher (MD5) is used to compute hash key from business keys
 type DataVault struct {
 	DbName     string
 	DbAddress  string
 	Db         *sql.DB
+	Dialect    dialect.Dialect
 	MetaReader dvmeta.DataVaultMetaReader
+	Hasher     *hashkey.Hasher
 }
 
-//CreateDV create data vault handler instance
+//CreateDV create data vault handler instance on MySQL database
 func CreateDV(address string, username string, password string,
 	dbName string, port int) (*DataVault, error) {
 
-	//TODO:  handle various database vendor
-	db, err := sql.Open("mysql", fmt.Sprintf(
-		"%s:%s@tcp(%s:%d)/%s?charset=utf8", username, password, address, port, dbName))
+	return CreateDialectDV(dialect.MYSQL, address, username, password, dbName, port)
+}
+
+//CreateDialectDV create data vault handler instance on database of given SQL dialect
+func CreateDialectDV(sqlDialect dialect.Dialect, address string, username string, password string,
+	dbName string, port int) (*DataVault, error) {
+
+	db, err := sql.Open(sqlDialect.DriverName(),
+		sqlDialect.DataSourceName(address, username, password, dbName, port))
+
+	if err != nil {
+		return nil, err
+	}
+
+	//check connection is valid or not
+	if pingErr := db.Ping(); pingErr != nil {
+		return nil, pingErr
+	}
+
+	dv, dvErr := CreateDVFromDb(sqlDialect, db, dbName)
+	if dvErr != nil {
+		return nil, dvErr
+	}
+	dv.DbAddress = address
+
+	return dv, nil
+}
+
+//CreateDSNDV create data vault handler instance from full connection string of given SQL dialect;
+//used when connection require setting not covered by CreateDialectDV, e.g. SSL certificate
+func CreateDSNDV(sqlDialect dialect.Dialect, dataSourceName string, dbName string) (*DataVault, error) {
+	db, err := sql.Open(sqlDialect.DriverName(), dataSourceName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	//check connection is valid or not
+	if pingErr := db.Ping(); pingErr != nil {
+		return nil, pingErr
+	}
+
+	return CreateDVFromDb(sqlDialect, db, dbName)
+}
+
+//CreateSQLiteDV create data vault handler instance on SQLite database file;
+//use ":memory:" as file path to create in-memory data vault
+func CreateSQLiteDV(filePath string) (*DataVault, error) {
+	db, err := sql.Open(dialect.SQLITE.DriverName(),
+		dialect.SQLITE.DataSourceName("", "", "", filePath, 0))
 
 	if err != nil {
 		return nil, err
@@ -37,24 +95,52 @@ func CreateDV(address string, username string, password string,
 		return nil, pingErr
 	}
 
-	meta := mysqlMeta.MetaReader{
-		DbName: dbName}
+	dv, dvErr := CreateDVFromDb(dialect.SQLITE, db, filePath)
+	if dvErr != nil {
+		return nil, dvErr
+	}
+	dv.DbAddress = filePath
+
+	return dv, nil
+}
+
+//CreateDVFromDb create data vault handler instance from opened database connection;
+//PostgreSQL data vault is read from public schema, SQLite connection pool is limited to one connection
+func CreateDVFromDb(sqlDialect dialect.Dialect, db *sql.DB, dbName string) (*DataVault, error) {
+	var meta dvmeta.DataVaultMetaReader
+	switch sqlDialect.Name() {
+	case dialect.MYSQL.Name():
+		meta = &mysqlMeta.MetaReader{
+			DbName: dbName}
+		break
+	case dialect.POSTGRES.Name():
+		meta = &postgresMeta.MetaReader{
+			SchemaName: "public"}
+		break
+	case dialect.SQLITE.Name():
+		meta = &sqliteMeta.MetaReader{}
+
+		//SQLite only allow single writer; in-memory database also live within one connection
+		db.SetMaxOpenConns(1)
+		break
+	default:
+		return nil, fmt.Errorf("Unsupported database vendor: %s", sqlDialect.Name())
+	}
 
 	dv := DataVault{
 		DbName:     dbName,
-		DbAddress:  address,
 		Db:         db,
-		MetaReader: &meta}
+		Dialect:    sqlDialect,
+		MetaReader: meta}
 
 	return &dv, nil
 }
 
 //InsertRecord to insert new record into database
 func (dv *DataVault) InsertRecord(dvInsertRecord *record.DvInsertRecord) error {
-	sqls, sqlErr := dvInsertRecord.GenerateMultiSQL()
-
-	if sqlErr != nil {
-		return sqlErr
+	dvInsertRecord.Dialect = dv.Dialect
+	if dvInsertRecord.Hasher == nil {
+		dvInsertRecord.Hasher = dv.Hasher
 	}
 
 	transaction, beginErr := dv.Db.Begin()
@@ -62,9 +148,20 @@ func (dv *DataVault) InsertRecord(dvInsertRecord *record.DvInsertRecord) error {
 		return beginErr
 	}
 
-	//TODO: test with various database vendor
+	integrityErr := dvInsertRecord.CheckIntegrity(dv.MetaReader, transaction)
+	if integrityErr != nil {
+		transaction.Rollback()
+		return integrityErr
+	}
+
+	sqls, sqlErr := dvInsertRecord.GenerateMultiParamSQL()
+	if sqlErr != nil {
+		transaction.Rollback()
+		return sqlErr
+	}
+
 	for _, sql := range sqls {
-		execErr := dv.execSQL(sql, transaction)
+		execErr := dv.execSQL(transaction, sql.SQL, sql.Args...)
 		if execErr != nil {
 			transaction.Rollback()
 			return execErr
@@ -79,8 +176,8 @@ func (dv *DataVault) InsertRecord(dvInsertRecord *record.DvInsertRecord) error {
 	return nil
 }
 
-func (dv *DataVault) execSQL(sql string, transaction *sql.Tx) error {
-	_, execErr := transaction.Exec(sql)
+func (dv *DataVault) execSQL(transaction *sql.Tx, query string, args ...interface{}) error {
+	_, execErr := transaction.Exec(query, args...)
 	if execErr != nil {
 		return execErr
 	}
@@ -0,0 +1,42 @@
+package datavault
+
+import (
+	"errors"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/query"
+)
+
+//RefreshBridge to build bridge rows of daily snapshot date from given start date until
+//end date (inclusive) within single transaction; it is incremental, only path which is
+//not yet in bridge is inserted, so refreshing same period again only pick up new links
+func (dv *DataVault) RefreshBridge(bridgeDef *definition.BridgeDefinition, from time.Time, to time.Time) error {
+	if to.Before(from) {
+		return errors.New("bridge refresh end date cannot be earlier than start date")
+	}
+
+	transaction, beginErr := dv.Db.Begin()
+	if beginErr != nil {
+		return beginErr
+	}
+
+	for snapshotDate := from; !snapshotDate.After(to); snapshotDate = snapshotDate.AddDate(0, 0, 1) {
+		bridgeRefresh := query.BridgeRefresh{
+			Definition:   bridgeDef,
+			SnapshotDate: snapshotDate}
+
+		sql, args, sqlErr := bridgeRefresh.GenerateParamSQL(dv.Dialect)
+		if sqlErr != nil {
+			transaction.Rollback()
+			return sqlErr
+		}
+
+		if execErr := dv.execSQL(transaction, sql, args...); execErr != nil {
+			transaction.Rollback()
+			return execErr
+		}
+	}
+
+	return transaction.Commit()
+}
@@ -0,0 +1,68 @@
+package datavault
+
+import (
+	"errors"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/query"
+)
+
+//CreatePitDefinition to create point in time (PIT) definition of a hub which
+//refer to every satelite of the hub found in database
+func (dv *DataVault) CreatePitDefinition(pitName string, hubName string,
+	hubRevision int) (*definition.PitDefinition, error) {
+
+	relationship, relErr := dv.MetaReader.GetRelationship(dv.Db, hubName, hubRevision)
+	if relErr != nil {
+		return nil, relErr
+	}
+
+	pitDef := definition.PitDefinition{
+		Name:         pitName,
+		Revision:     0,
+		HubReference: &definition.HubReference{HubName: hubName, Revision: hubRevision},
+		Satelites:    []definition.SateliteReference{}}
+
+	for _, satDef := range relationship.Satelites {
+		pitDef.Satelites = append(pitDef.Satelites, definition.SateliteReference{
+			SateliteName: satDef.Name,
+			Revision:     satDef.Revision})
+	}
+
+	return &pitDef, nil
+}
+
+//RefreshPit to rebuild point in time (PIT) rows of daily snapshot date from
+//given start date until end date (inclusive) within single transaction
+func (dv *DataVault) RefreshPit(pitDef *definition.PitDefinition, from time.Time, to time.Time) error {
+	if to.Before(from) {
+		return errors.New("PIT refresh end date cannot be earlier than start date")
+	}
+
+	transaction, beginErr := dv.Db.Begin()
+	if beginErr != nil {
+		return beginErr
+	}
+
+	for snapshotDate := from; !snapshotDate.After(to); snapshotDate = snapshotDate.AddDate(0, 0, 1) {
+		pitRefresh := query.PitRefresh{
+			Definition:   pitDef,
+			SnapshotDate: snapshotDate}
+
+		sqls, sqlErr := pitRefresh.GenerateMultiParamSQL(dv.Dialect)
+		if sqlErr != nil {
+			transaction.Rollback()
+			return sqlErr
+		}
+
+		for _, sql := range sqls {
+			if execErr := dv.execSQL(transaction, sql.SQL, sql.Args...); execErr != nil {
+				transaction.Rollback()
+				return execErr
+			}
+		}
+	}
+
+	return transaction.Commit()
+}
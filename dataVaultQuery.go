@@ -0,0 +1,153 @@
+package datavault
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/guinso/datavault/query"
+	"github.com/guinso/datavault/record"
+)
+
+//GetHubState to read current state of a hub entry by its business key values;
+//current row of every satelite refer to the hub is merged into one record;
+//nil is returned if business key values not found in hub
+func (dv *DataVault) GetHubState(hubName string, revision int,
+	businessKeys []record.HubBusinessKeyInsertRecord) (*query.HubState, error) {
+
+	return dv.readHubState(hubName, revision, businessKeys,
+		func(satQuery *query.SateliteQuery) (string, []interface{}, error) {
+			return satQuery.GenerateCurrentParamSQL(dv.Dialect)
+		})
+}
+
+//GetHubStateAsOf to read state of a hub entry at given time; satelite row which is valid
+//at that time (latest load date not after it) is merged into one record;
+//nil is returned if business key values not found in hub
+func (dv *DataVault) GetHubStateAsOf(hubName string, revision int,
+	businessKeys []record.HubBusinessKeyInsertRecord, asOf time.Time) (*query.HubState, error) {
+
+	return dv.readHubState(hubName, revision, businessKeys,
+		func(satQuery *query.SateliteQuery) (string, []interface{}, error) {
+			return satQuery.GenerateAsOfParamSQL(dv.Dialect, asOf)
+		})
+}
+
+//GetHubHistory to read change history of a hub entry within given period; every satelite
+//row valid within the period is listed in Satelites ordered by load date, while Attributes
+//is state at end of the period; zero from or to time means the period is not bounded
+//at that side, so zero values return full history;
+//nil is returned if business key values not found in hub
+func (dv *DataVault) GetHubHistory(hubName string, revision int,
+	businessKeys []record.HubBusinessKeyInsertRecord, from time.Time, to time.Time) (*query.HubState, error) {
+
+	return dv.readHubState(hubName, revision, businessKeys,
+		func(satQuery *query.SateliteQuery) (string, []interface{}, error) {
+			return satQuery.GenerateHistoryParamSQL(dv.Dialect, from, to)
+		})
+}
+
+//readHubState read hub entry and merge its satelite row(s) selected by generated statement
+func (dv *DataVault) readHubState(hubName string, revision int,
+	businessKeys []record.HubBusinessKeyInsertRecord,
+	generateSQL func(*query.SateliteQuery) (string, []interface{}, error)) (*query.HubState, error) {
+
+	hashKey, hashErr := dv.getHubHashKey(hubName, businessKeys)
+	if hashErr != nil {
+		return nil, hashErr
+	}
+
+	hubState, hubErr := dv.getHubEntry(hubName, revision, hashKey)
+	if hubErr != nil || hubState == nil {
+		return nil, hubErr
+	}
+
+	relationship, relErr := dv.MetaReader.GetRelationship(dv.Db, hubName, revision)
+	if relErr != nil {
+		return nil, relErr
+	}
+
+	for index := range relationship.Satelites {
+		satQuery := query.SateliteQuery{
+			Definition: &relationship.Satelites[index],
+			HashKey:    hashKey}
+
+		sqlStr, args, sqlErr := generateSQL(&satQuery)
+		if sqlErr != nil {
+			return nil, sqlErr
+		}
+
+		satStates, readErr := dv.readSateliteStates(&satQuery, sqlStr, args)
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		for _, satState := range satStates {
+			hubState.AddSatelite(satState)
+		}
+	}
+
+	return hubState, nil
+}
+
+//readSateliteStates read all satelite rows selected by statement
+func (dv *DataVault) readSateliteStates(satQuery *query.SateliteQuery,
+	sqlStr string, args []interface{}) ([]query.SateliteState, error) {
+	rows, queryErr := dv.Db.Query(sqlStr, args...)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	var result []query.SateliteState
+	for rows.Next() {
+		satState, scanErr := satQuery.ScanState(rows.Scan)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+
+		result = append(result, *satState)
+	}
+
+	return result, rows.Err()
+}
+
+//getHubHashKey compute hub hash key from business key values
+func (dv *DataVault) getHubHashKey(hubName string,
+	businessKeys []record.HubBusinessKeyInsertRecord) (string, error) {
+	hub := record.HubInsertRecord{
+		HubName:         hubName,
+		BusinessKeyVues: businessKeys}
+
+	if hashErr := hub.FillHashKey(dv.Hasher); hashErr != nil {
+		return "", hashErr
+	}
+
+	return hub.HashKey, nil
+}
+
+//getHubEntry read hub entry by its hash key; nil is returned if not found
+func (dv *DataVault) getHubEntry(hubName string, revision int, hashKey string) (*query.HubState, error) {
+	hubDef, defErr := dv.MetaReader.GetHubDefinition(hubName, revision, dv.Db)
+	if defErr != nil {
+		return nil, defErr
+	}
+
+	hubQuery := query.HubQuery{
+		Definition: hubDef,
+		HashKey:    hashKey}
+
+	sqlStr, args, sqlErr := hubQuery.GenerateParamSQL(dv.Dialect)
+	if sqlErr != nil {
+		return nil, sqlErr
+	}
+
+	hubState, scanErr := hubQuery.ScanState(dv.Db.QueryRow(sqlStr, args...).Scan)
+	if scanErr == sql.ErrNoRows {
+		return nil, nil
+	} else if scanErr != nil {
+		return nil, fmt.Errorf("fail to read hub %s: %s", hubName, scanErr.Error())
+	}
+
+	return hubState, nil
+}
@@ -0,0 +1,794 @@
+package datavault
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/datavault/internal/dvtest"
+	"github.com/guinso/datavault/query"
+	"github.com/guinso/datavault/record"
+	"github.com/guinso/rdbmstool"
+)
+
+func createTestSQLiteDV(t *testing.T) *DataVault {
+	dv, err := CreateSQLiteDV(":memory:")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	hubDef := definition.HubDefinition{Name: "Customer", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "Name"}}}
+	satDef := definition.SateliteDefinition{
+		Name:         "Customer",
+		Revision:     0,
+		HubReference: &definition.HubReference{HubName: "Customer", Revision: 0},
+		HasHashDiff:  true,
+		Attributes: []definition.SateliteAttributeDefinition{
+			definition.SateliteAttributeDefinition{Name: "Remark", DataType: rdbmstool.TEXT, IsNullable: true}}}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, definition.DataVaultDefinition{
+		Hubs:      []definition.HubDefinition{hubDef},
+		Satelites: []definition.SateliteDefinition{satDef}})
+
+	return dv
+}
+
+func createTestCustomerRecord(loadDate time.Time, remark string) *record.DvInsertRecord {
+	businessKeys := []record.HubBusinessKeyInsertRecord{
+		record.HubBusinessKeyInsertRecord{BusinessKey: "Name", BusinessValue: "O'Brien"}}
+
+	return &record.DvInsertRecord{
+		SkipExistingKeys:       true,
+		SkipUnchangedSatelites: true,
+		Hubs: []record.HubInsertRecord{
+			record.HubInsertRecord{
+				HubName:         "Customer",
+				RecordSource:    "crm",
+				LoadDate:        loadDate,
+				BusinessKeyVues: businessKeys}},
+		Satelites: []record.SateliteInsertRecord{
+			record.SateliteInsertRecord{
+				SateliteName:         "Customer",
+				HubName:              "Customer",
+				HubBusinessKeyValues: businessKeys,
+				RecordSource:         "crm",
+				LoadDate:             loadDate,
+				HasHashDiff:          true,
+				Attributes: []record.SateliteAttrInsertRecord{
+					record.SateliteAttrInsertRecord{
+						AttributeName: "Remark",
+						Value:         remark,
+						Meta: &definition.SateliteAttributeDefinition{
+							Name: "Remark", DataType: rdbmstool.TEXT, IsNullable: true}}}}}}
+}
+
+func TestSQLiteInsertRecord(t *testing.T) {
+	dv := createTestSQLiteDV(t)
+	defer dv.Db.Close()
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day2.AddDate(0, 0, 1)
+
+	for _, dvRecord := range []*record.DvInsertRecord{
+		createTestCustomerRecord(day1, "first"),
+		createTestCustomerRecord(day2, "first"), //unchanged, skipped
+		createTestCustomerRecord(day3, "second")} {
+		if err := dv.InsertRecord(dvRecord); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	var hubCount, satCount, openCount int
+	dv.Db.QueryRow("SELECT COUNT(*) FROM hub_customer_rev0").Scan(&hubCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_customer_rev0").Scan(&satCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_customer_rev0 WHERE end_date IS NULL").Scan(&openCount)
+
+	if hubCount != 1 {
+		t.Errorf("Expect 1 hub row, given %d instead", hubCount)
+	}
+
+	if satCount != 2 {
+		t.Errorf("Expect 2 satelite rows, given %d instead", satCount)
+	}
+
+	if openCount != 1 {
+		t.Errorf("Expect 1 open satelite row, given %d instead", openCount)
+	}
+}
+
+func TestSQLiteInsertCaseOnlyChange(t *testing.T) {
+	dv := createTestSQLiteDV(t)
+	defer dv.Db.Close()
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	nullRecord := createTestCustomerRecord(day1.AddDate(0, 0, 2), "")
+	nullRecord.Satelites[0].Attributes[0].Value = nil
+
+	for _, dvRecord := range []*record.DvInsertRecord{
+		createTestCustomerRecord(day1, "acme"),
+		createTestCustomerRecord(day1.AddDate(0, 0, 1), "ACME"),
+		nullRecord,
+		createTestCustomerRecord(day1.AddDate(0, 0, 3), "")} {
+		if err := dv.InsertRecord(dvRecord); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	var satCount int
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_customer_rev0").Scan(&satCount)
+	if satCount != 4 {
+		t.Errorf("Expect 4 satelite rows (every change is kept), given %d instead", satCount)
+	}
+}
+
+func TestSQLiteInsertSHA256Record(t *testing.T) {
+	dv, err := CreateSQLiteDV(":memory:")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer dv.Db.Close()
+
+	dv.Hasher = hashkey.CreateHasher()
+	dv.Hasher.Algorithm = hashkey.SHA256
+
+	dvDef := definition.DataVaultDefinition{
+		HashAlgorithm: dv.Hasher.Algorithm,
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Customer", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "Name"}}}},
+		Satelites: []definition.SateliteDefinition{
+			definition.SateliteDefinition{
+				Name:         "Customer",
+				HubReference: &definition.HubReference{HubName: "Customer"},
+				HasHashDiff:  true,
+				Attributes: []definition.SateliteAttributeDefinition{
+					definition.SateliteAttributeDefinition{Name: "Remark", DataType: rdbmstool.TEXT, IsNullable: true}}}}}
+
+	sqls, sqlErr := dvDef.GenerateDialectSQL(dv.Dialect)
+	if sqlErr != nil {
+		t.Fatal(sqlErr.Error())
+	}
+
+	for _, sql := range sqls {
+		if !strings.Contains(sql, "\"customer_hash_key\" CHAR(64) NOT NULL") {
+			t.Errorf("Expect hash key column sized for SHA-256:\n%s", sql)
+		}
+	}
+
+	if !strings.Contains(sqls[1], "\"hash_diff\" CHAR(64) NOT NULL") {
+		t.Errorf("Expect hash diff column sized for SHA-256:\n%s", sqls[1])
+	}
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, dvDef)
+
+	if insertErr := dv.InsertRecord(createTestCustomerRecord(time.Now(), "first")); insertErr != nil {
+		t.Fatal(insertErr.Error())
+	}
+
+	var hashKey, hashDiff string
+	if scanErr := dv.Db.QueryRow("SELECT customer_hash_key, hash_diff FROM sat_customer_rev0").Scan(
+		&hashKey, &hashDiff); scanErr != nil {
+		t.Fatal(scanErr.Error())
+	}
+
+	if expected := dv.Hasher.HashKey("O'Brien"); strings.Compare(hashKey, expected) != 0 {
+		t.Errorf("Expect SHA-256 hash key %s, given %s instead", expected, hashKey)
+	}
+
+	if len(hashDiff) != hashkey.SHA256.Length() {
+		t.Errorf("Expect hash diff length %d, given %d instead", hashkey.SHA256.Length(), len(hashDiff))
+	}
+}
+
+func TestSQLiteCreateDialectDV(t *testing.T) {
+	dv, err := CreateDialectDV(dialect.SQLITE, "", "", "", ":memory:", 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer dv.Db.Close()
+
+	if maxConn := dv.Db.Stats().MaxOpenConnections; maxConn != 1 {
+		t.Errorf("Expect SQLite data vault is limited to 1 connection, given %d instead", maxConn)
+	}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Customer", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "Name"}}}}})
+
+	if _, hubErr := dv.MetaReader.GetHubDefinition("Customer", 0, dv.Db); hubErr != nil {
+		t.Errorf("Expect hub is found in same in-memory database: %s", hubErr.Error())
+	}
+}
+
+func TestSQLiteGetHubState(t *testing.T) {
+	dv := createTestSQLiteDV(t)
+	defer dv.Db.Close()
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for _, dvRecord := range []*record.DvInsertRecord{
+		createTestCustomerRecord(day1, "first"),
+		createTestCustomerRecord(day1.AddDate(0, 0, 1), "second")} {
+		if err := dv.InsertRecord(dvRecord); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	state, err := dv.GetHubState("Customer", 0, []record.HubBusinessKeyInsertRecord{
+		record.HubBusinessKeyInsertRecord{BusinessKey: "Name", BusinessValue: "o'brien "}})
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if state == nil {
+		t.Error("Expect hub state found")
+		return
+	}
+
+	if state.BusinessKeys["Name"] != "O'Brien" {
+		t.Errorf("Expect business key is O'Brien, given %v instead", state.BusinessKeys)
+	}
+
+	if len(state.Satelites) != 1 || state.Attributes["Remark"] != "second" {
+		t.Errorf("Expect current remark is second, given %v instead", state.Attributes)
+	}
+
+	missing, missingErr := dv.GetHubState("Customer", 0, []record.HubBusinessKeyInsertRecord{
+		record.HubBusinessKeyInsertRecord{BusinessKey: "Name", BusinessValue: "Nobody"}})
+	if missingErr != nil || missing != nil {
+		t.Errorf("Expect no hub state for unknown business key, given %v, %v", missing, missingErr)
+	}
+}
+
+func TestSQLiteGetHubStateAsOf(t *testing.T) {
+	dv := createTestSQLiteDV(t)
+	defer dv.Db.Close()
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for index, remark := range []string{"first", "second", "third"} {
+		if err := dv.InsertRecord(createTestCustomerRecord(day1.AddDate(0, 0, index*2), remark)); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	businessKeys := []record.HubBusinessKeyInsertRecord{
+		record.HubBusinessKeyInsertRecord{BusinessKey: "Name", BusinessValue: "O'Brien"}}
+
+	state, err := dv.GetHubStateAsOf("Customer", 0, businessKeys, day1.AddDate(0, 0, 3))
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if state == nil || state.Attributes["Remark"] != "second" {
+		t.Errorf("Expect remark is second as of day 4, given %v instead", state)
+		return
+	}
+
+	if state.Satelites[0].EndDate == nil || !state.Satelites[0].EndDate.Equal(day1.AddDate(0, 0, 4)) {
+		t.Errorf("Expect row is end dated at day 5, given %v instead", state.Satelites[0].EndDate)
+	}
+
+	history, historyErr := dv.GetHubHistory("Customer", 0, businessKeys, day1.AddDate(0, 0, 1), day1.AddDate(0, 0, 3))
+	if historyErr != nil {
+		t.Error(historyErr.Error())
+		return
+	}
+
+	if len(history.Satelites) != 2 || history.Satelites[0].Attributes["Remark"] != "first" ||
+		history.Attributes["Remark"] != "second" {
+		t.Errorf("Expect history of first and second, given %v instead", history.Satelites)
+	}
+
+	fullHistory, fullErr := dv.GetHubHistory("Customer", 0, businessKeys, time.Time{}, time.Time{})
+	if fullErr != nil {
+		t.Error(fullErr.Error())
+		return
+	}
+
+	if len(fullHistory.Satelites) != 3 {
+		t.Errorf("Expect 3 history rows, given %d instead", len(fullHistory.Satelites))
+	}
+}
+
+func TestSQLiteRefreshPit(t *testing.T) {
+	dv := createTestSQLiteDV(t)
+	defer dv.Db.Close()
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for index, remark := range []string{"first", "second"} {
+		if err := dv.InsertRecord(createTestCustomerRecord(day1.AddDate(0, 0, index*2), remark)); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	pitDef, pitErr := dv.CreatePitDefinition("Customer", "Customer", 0)
+	if pitErr != nil {
+		t.Error(pitErr.Error())
+		return
+	}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, definition.DataVaultDefinition{}, pitDef.GenerateDialectSQL)
+
+	//refresh twice to ensure existing snapshot rows are replaced
+	for round := 0; round < 2; round++ {
+		if err := dv.RefreshPit(pitDef, day1.AddDate(0, 0, -1), day1.AddDate(0, 0, 3)); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	var rowCount, secondCount int
+	dv.Db.QueryRow("SELECT COUNT(*) FROM pit_customer_rev0").Scan(&rowCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM pit_customer_rev0 WHERE sat_customer_rev0_load_date = ?",
+		day1.AddDate(0, 0, 2)).Scan(&secondCount)
+
+	//hub is loaded at day 1, so snapshot before day 1 has no row
+	if rowCount != 4 {
+		t.Errorf("Expect 4 PIT rows, given %d instead", rowCount)
+	}
+
+	if secondCount != 2 {
+		t.Errorf("Expect 2 PIT rows refer to second satelite row, given %d instead", secondCount)
+	}
+}
+
+func createTestBridgeRecord(loadDate time.Time, invoiceNo string, itemNo string, productNo string) *record.DvInsertRecord {
+	businessKeys := func(key string, value string) []record.HubBusinessKeyInsertRecord {
+		return []record.HubBusinessKeyInsertRecord{
+			record.HubBusinessKeyInsertRecord{BusinessKey: key, BusinessValue: value}}
+	}
+
+	return &record.DvInsertRecord{
+		SkipExistingKeys: true,
+		Hubs: []record.HubInsertRecord{
+			record.HubInsertRecord{HubName: "Invoice", RecordSource: "erp", LoadDate: loadDate,
+				BusinessKeyVues: businessKeys("InvoiceNo", invoiceNo)},
+			record.HubInsertRecord{HubName: "OrderItem", RecordSource: "erp", LoadDate: loadDate,
+				BusinessKeyVues: businessKeys("ItemNo", itemNo)},
+			record.HubInsertRecord{HubName: "Product", RecordSource: "erp", LoadDate: loadDate,
+				BusinessKeyVues: businessKeys("ProductNo", productNo)}},
+		Links: []record.LinkInsertRecord{
+			record.LinkInsertRecord{LinkName: "InvoiceOrderItem", RecordSource: "erp", LoadDate: loadDate,
+				ReferenceHashKey: []record.LinkReferenceInsertRecord{
+					record.LinkReferenceInsertRecord{HubName: "Invoice",
+						BusinessKeyValues: businessKeys("InvoiceNo", invoiceNo)},
+					record.LinkReferenceInsertRecord{HubName: "OrderItem",
+						BusinessKeyValues: businessKeys("ItemNo", itemNo)}}},
+			record.LinkInsertRecord{LinkName: "OrderItemProduct", RecordSource: "erp", LoadDate: loadDate,
+				ReferenceHashKey: []record.LinkReferenceInsertRecord{
+					record.LinkReferenceInsertRecord{HubName: "OrderItem",
+						BusinessKeyValues: businessKeys("ItemNo", itemNo)},
+					record.LinkReferenceInsertRecord{HubName: "Product",
+						BusinessKeyValues: businessKeys("ProductNo", productNo)}}}}}
+}
+
+func TestSQLiteRefreshBridge(t *testing.T) {
+	dv, err := CreateSQLiteDV(":memory:")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer dv.Db.Close()
+
+	dvDef := definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Invoice", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "InvoiceNo"}}},
+			definition.HubDefinition{Name: "OrderItem", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "ItemNo"}}},
+			definition.HubDefinition{Name: "Product", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "ProductNo"}}}},
+		Links: []definition.LinkDefinition{
+			definition.LinkDefinition{Name: "InvoiceOrderItem", HubReferences: []definition.HubReference{
+				definition.HubReference{HubName: "Invoice"}, definition.HubReference{HubName: "OrderItem"}}},
+			definition.LinkDefinition{Name: "OrderItemProduct", HubReferences: []definition.HubReference{
+				definition.HubReference{HubName: "OrderItem"}, definition.HubReference{HubName: "Product"}}}}}
+	bridgeDef := definition.BridgeDefinition{
+		Name:         "InvoiceProduct",
+		HubReference: &definition.HubReference{HubName: "Invoice"},
+		Path: []definition.BridgeStep{
+			definition.BridgeStep{
+				LinkReference: definition.LinkReference{LinkName: "InvoiceOrderItem"},
+				HubReference:  definition.HubReference{HubName: "OrderItem"}},
+			definition.BridgeStep{
+				LinkReference: definition.LinkReference{LinkName: "OrderItemProduct"},
+				HubReference:  definition.HubReference{HubName: "Product"}}}}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, dvDef, bridgeDef.GenerateDialectSQL)
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	if insertErr := dv.InsertRecord(createTestBridgeRecord(day1, "INV-1", "ITEM-1", "P-1")); insertErr != nil {
+		t.Fatal(insertErr.Error())
+	}
+
+	if refreshErr := dv.RefreshBridge(&bridgeDef, day1, day1.AddDate(0, 0, 1)); refreshErr != nil {
+		t.Fatal(refreshErr.Error())
+	}
+
+	if insertErr := dv.InsertRecord(createTestBridgeRecord(day1.AddDate(0, 0, 1),
+		"INV-1", "ITEM-2", "P-2")); insertErr != nil {
+		t.Fatal(insertErr.Error())
+	}
+
+	if refreshErr := dv.RefreshBridge(&bridgeDef, day1, day1.AddDate(0, 0, 1)); refreshErr != nil {
+		t.Fatal(refreshErr.Error())
+	}
+
+	var dayOneCount, dayTwoCount int
+	dv.Db.QueryRow("SELECT COUNT(*) FROM bridge_invoice_product_rev0 WHERE snapshot_date = ?", day1).Scan(&dayOneCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM bridge_invoice_product_rev0 WHERE snapshot_date = ?",
+		day1.AddDate(0, 0, 1)).Scan(&dayTwoCount)
+
+	if dayOneCount != 1 || dayTwoCount != 2 {
+		t.Errorf("Expect 1 and 2 bridge rows, given %d and %d instead", dayOneCount, dayTwoCount)
+	}
+}
+
+func TestSQLiteLinkSatelite(t *testing.T) {
+	dv, err := CreateSQLiteDV(":memory:")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer dv.Db.Close()
+
+	hubDefs := []definition.HubDefinition{
+		definition.HubDefinition{Name: "Invoice", BusinessKeys: []definition.BusinessKeyDefinition{
+			definition.BusinessKeyDefinition{Name: "InvoiceNo"}}},
+		definition.HubDefinition{Name: "OrderItem", BusinessKeys: []definition.BusinessKeyDefinition{
+			definition.BusinessKeyDefinition{Name: "ItemNo"}}}}
+	linkDef := definition.LinkDefinition{Name: "InvoiceOrderItem", HubReferences: []definition.HubReference{
+		definition.HubReference{HubName: "Invoice"}, definition.HubReference{HubName: "OrderItem"}}}
+	satDef := definition.SateliteDefinition{
+		Name:          "InvoiceOrderItem",
+		LinkReference: &definition.LinkReference{LinkName: "InvoiceOrderItem"},
+		HasHashDiff:   true,
+		Attributes: []definition.SateliteAttributeDefinition{
+			definition.SateliteAttributeDefinition{Name: "Quantity", DataType: rdbmstool.INTEGER, Length: 11}}}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, definition.DataVaultDefinition{
+		Hubs:      hubDefs,
+		Links:     []definition.LinkDefinition{linkDef},
+		Satelites: []definition.SateliteDefinition{satDef}})
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for index, quantity := range []int{2, 2, 5} {
+		dvRecord := createTestBridgeRecord(day1.AddDate(0, 0, index), "INV-1", "ITEM-1", "P-1")
+		dvRecord.SkipUnchangedSatelites = true
+		dvRecord.Hubs = dvRecord.Hubs[:2]
+		dvRecord.Links = dvRecord.Links[:1]
+		dvRecord.Satelites = []record.SateliteInsertRecord{
+			record.SateliteInsertRecord{
+				SateliteName:   "InvoiceOrderItem",
+				LinkName:       "InvoiceOrderItem",
+				LinkReferences: dvRecord.Links[0].ReferenceHashKey,
+				RecordSource:   "erp",
+				LoadDate:       day1.AddDate(0, 0, index),
+				HasHashDiff:    true,
+				Attributes: []record.SateliteAttrInsertRecord{
+					record.SateliteAttrInsertRecord{
+						AttributeName: "Quantity",
+						Value:         quantity,
+						Meta:          &satDef.Attributes[0]}}}}
+
+		if insertErr := dv.InsertRecord(dvRecord); insertErr != nil {
+			t.Fatal(insertErr.Error())
+		}
+	}
+
+	var satCount, openCount int
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_invoice_order_item_rev0").Scan(&satCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_invoice_order_item_rev0 WHERE end_date IS NULL").Scan(&openCount)
+
+	if satCount != 2 || openCount != 1 {
+		t.Errorf("Expect 2 link satelite rows with 1 open row, given %d and %d instead", satCount, openCount)
+	}
+
+	relationship, relErr := dv.MetaReader.GetRelationship(dv.Db, "Invoice", 0)
+	if relErr != nil {
+		t.Fatal(relErr.Error())
+	}
+
+	if len(relationship.Links) != 1 || len(relationship.Links[0].LinkSatelites) != 1 ||
+		relationship.Links[0].LinkSatelites[0].LinkReference == nil {
+		t.Errorf("Expect link satelite is resolved from invoice hub, given %v instead", relationship.Links)
+	}
+}
+
+func TestSQLiteEffectivitySatelite(t *testing.T) {
+	dv, err := CreateSQLiteDV(":memory:")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer dv.Db.Close()
+
+	dvDef := definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Invoice", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "InvoiceNo"}}},
+			definition.HubDefinition{Name: "Employee", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "EmployeeNo"}}}},
+		Links: []definition.LinkDefinition{
+			definition.LinkDefinition{Name: "InvoiceEmployee", HubReferences: []definition.HubReference{
+				definition.HubReference{HubName: "Invoice"}, definition.HubReference{HubName: "Employee"}}}}}
+	esatDef := definition.EffectivitySateliteDefinition{
+		Name:          "InvoiceEmployee",
+		LinkReference: &definition.LinkReference{LinkName: "InvoiceEmployee"},
+		DrivingKey:    &definition.HubReference{HubName: "Invoice"}}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, dvDef, esatDef.GenerateDialectSQL)
+
+	businessKeys := func(key string, value string) []record.HubBusinessKeyInsertRecord {
+		return []record.HubBusinessKeyInsertRecord{
+			record.HubBusinessKeyInsertRecord{BusinessKey: key, BusinessValue: value}}
+	}
+	createRecord := func(loadDate time.Time, employeeNo string) *record.DvInsertRecord {
+		refs := []record.LinkReferenceInsertRecord{
+			record.LinkReferenceInsertRecord{HubName: "Invoice",
+				BusinessKeyValues: businessKeys("InvoiceNo", "INV-1")},
+			record.LinkReferenceInsertRecord{HubName: "Employee",
+				BusinessKeyValues: businessKeys("EmployeeNo", employeeNo)}}
+
+		return &record.DvInsertRecord{
+			SkipExistingKeys: true,
+			Hubs: []record.HubInsertRecord{
+				record.HubInsertRecord{HubName: "Invoice", RecordSource: "erp", LoadDate: loadDate,
+					BusinessKeyVues: businessKeys("InvoiceNo", "INV-1")},
+				record.HubInsertRecord{HubName: "Employee", RecordSource: "erp", LoadDate: loadDate,
+					BusinessKeyVues: businessKeys("EmployeeNo", employeeNo)}},
+			Links: []record.LinkInsertRecord{
+				record.LinkInsertRecord{LinkName: "InvoiceEmployee", RecordSource: "erp", LoadDate: loadDate,
+					ReferenceHashKey: refs}},
+			EffectivitySatelites: []record.EffectivitySateliteInsertRecord{
+				record.EffectivitySateliteInsertRecord{
+					SateliteName:      "InvoiceEmployee",
+					RecordSource:      "erp",
+					LinkName:          "InvoiceEmployee",
+					LinkReferences:    refs,
+					DrivingKeyHubName: "Invoice",
+					LoadDate:          loadDate}}}
+	}
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for index, employeeNo := range []string{"E-1", "E-1", "E-2"} {
+		if insertErr := dv.InsertRecord(createRecord(day1.AddDate(0, 0, index), employeeNo)); insertErr != nil {
+			t.Fatal(insertErr.Error())
+		}
+	}
+
+	var rowCount, openCount int
+	var endDate time.Time
+	dv.Db.QueryRow("SELECT COUNT(*) FROM esat_invoice_employee_rev0").Scan(&rowCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM esat_invoice_employee_rev0 WHERE end_date IS NULL").Scan(&openCount)
+	dv.Db.QueryRow("SELECT end_date FROM esat_invoice_employee_rev0 WHERE end_date IS NOT NULL").Scan(&endDate)
+
+	if rowCount != 2 || openCount != 1 {
+		t.Errorf("Expect 2 relationship rows with 1 open row, given %d and %d instead", rowCount, openCount)
+	}
+
+	if !endDate.Equal(day1.AddDate(0, 0, 2)) {
+		t.Errorf("Expect first relationship closed at day 3, given %v instead", endDate)
+	}
+
+	relationship, relErr := dv.MetaReader.GetRelationship(dv.Db, "Invoice", 0)
+	if relErr != nil {
+		t.Fatal(relErr.Error())
+	}
+
+	if len(relationship.Links) != 1 || len(relationship.Links[0].EffectivitySatelites) != 1 ||
+		relationship.Links[0].EffectivitySatelites[0].DrivingKey.HubName != "Invoice" {
+		t.Errorf("Expect effectivity satelite is resolved from invoice hub, given %v instead", relationship.Links)
+	}
+}
+
+func TestSQLiteMultiActiveSatelite(t *testing.T) {
+	dv := createTestSQLiteDV(t)
+	defer dv.Db.Close()
+
+	phoneType := definition.SateliteAttributeDefinition{Name: "PhoneType", DataType: rdbmstool.VARCHAR, Length: 20}
+	phoneNo := definition.SateliteAttributeDefinition{Name: "PhoneNo", DataType: rdbmstool.VARCHAR, Length: 20}
+	satDef := definition.SateliteDefinition{
+		Name:            "CustomerPhone",
+		HubReference:    &definition.HubReference{HubName: "Customer"},
+		HasHashDiff:     true,
+		MultiActiveKeys: []string{"PhoneType"},
+		Attributes:      []definition.SateliteAttributeDefinition{phoneType, phoneNo}}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, definition.DataVaultDefinition{
+		Satelites: []definition.SateliteDefinition{satDef}})
+
+	readDef, readErr := dv.MetaReader.GetSateliteDefinition("CustomerPhone", 0, dv.Db)
+	if readErr != nil {
+		t.Fatal(readErr.Error())
+	}
+	if len(readDef.MultiActiveKeys) != 1 || readDef.MultiActiveKeys[0] != "PhoneType" {
+		t.Errorf("Expect multi-active key PhoneType, given %v instead", readDef.MultiActiveKeys)
+	}
+
+	createRecord := func(loadDate time.Time, phones map[string]string) *record.DvInsertRecord {
+		dvRecord := createTestCustomerRecord(loadDate, "remark")
+		businessKeys := dvRecord.Satelites[0].HubBusinessKeyValues
+		dvRecord.Satelites = nil
+		for typeValue, noValue := range phones {
+			dvRecord.Satelites = append(dvRecord.Satelites, record.SateliteInsertRecord{
+				SateliteName:         "CustomerPhone",
+				HubName:              "Customer",
+				HubBusinessKeyValues: businessKeys,
+				RecordSource:         "crm",
+				LoadDate:             loadDate,
+				HasHashDiff:          true,
+				MultiActiveKeys:      []string{"PhoneType"},
+				Attributes: []record.SateliteAttrInsertRecord{
+					record.SateliteAttrInsertRecord{AttributeName: "PhoneType", Value: typeValue, Meta: &phoneType},
+					record.SateliteAttrInsertRecord{AttributeName: "PhoneNo", Value: noValue, Meta: &phoneNo}}})
+		}
+
+		return dvRecord
+	}
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for index, phones := range []map[string]string{
+		map[string]string{"mobile": "012-3456789", "home": "03-1234567"},
+		map[string]string{"mobile": "012-3456789", "home": "03-1234567"}, //unchanged, skipped
+		map[string]string{"mobile": "019-8765432"}} {
+		if err := dv.InsertRecord(createRecord(day1.AddDate(0, 0, index), phones)); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	var rowCount, openCount int
+	var openHome string
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_customer_phone_rev0").Scan(&rowCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM sat_customer_phone_rev0 WHERE end_date IS NULL").Scan(&openCount)
+	dv.Db.QueryRow("SELECT phone_no FROM sat_customer_phone_rev0 " +
+		"WHERE end_date IS NULL AND phone_type = 'home'").Scan(&openHome)
+
+	if rowCount != 3 || openCount != 2 {
+		t.Errorf("Expect 3 phone rows with 2 open rows, given %d and %d instead", rowCount, openCount)
+	}
+
+	if openHome != "03-1234567" {
+		t.Errorf("Expect home phone is still active, given %s instead", openHome)
+	}
+
+	for _, expected := range []struct {
+		asOf   time.Time
+		phones map[string]string
+	}{
+		{time.Time{}, map[string]string{"mobile": "019-8765432", "home": "03-1234567"}},
+		{day1.AddDate(0, 0, 1), map[string]string{"mobile": "012-3456789", "home": "03-1234567"}}} {
+		var state *query.HubState
+		var stateErr error
+		businessKeys := createTestCustomerRecord(day1, "").Hubs[0].BusinessKeyVues
+		if expected.asOf.IsZero() {
+			state, stateErr = dv.GetHubState("Customer", 0, businessKeys)
+		} else {
+			state, stateErr = dv.GetHubStateAsOf("Customer", 0, businessKeys, expected.asOf)
+		}
+		if stateErr != nil || state == nil {
+			t.Fatalf("Expect hub state found, given %v instead", stateErr)
+		}
+
+		phones := map[string]string{}
+		for _, satState := range state.Satelites {
+			if satState.SateliteName == "CustomerPhone" {
+				phones[satState.Attributes["PhoneType"].(string)] = satState.Attributes["PhoneNo"].(string)
+			}
+		}
+
+		if len(phones) != len(expected.phones) || phones["mobile"] != expected.phones["mobile"] ||
+			phones["home"] != expected.phones["home"] {
+			t.Errorf("Expect phones %v as of %v, given %v instead", expected.phones, expected.asOf, phones)
+		}
+	}
+
+	invalid := createRecord(day1.AddDate(0, 0, 5), map[string]string{"office": "03-7654321"})
+	invalid.Satelites[0].MultiActiveKeys = nil
+	if err := dv.InsertRecord(invalid); err == nil {
+		t.Error("Expect integrity error for satelite record without multi-active key")
+	}
+}
+
+func TestSQLiteReferenceAndNonHistorizedLink(t *testing.T) {
+	dv, err := CreateSQLiteDV(":memory:")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer dv.Db.Close()
+
+	currencyCode := definition.SateliteAttributeDefinition{Name: "CurrencyCode", DataType: rdbmstool.CHAR, Length: 3}
+	description := definition.SateliteAttributeDefinition{Name: "Description", DataType: rdbmstool.VARCHAR, Length: 100}
+	paymentNo := definition.SateliteAttributeDefinition{Name: "PaymentNo", DataType: rdbmstool.VARCHAR, Length: 20}
+	amount := definition.SateliteAttributeDefinition{Name: "Amount", DataType: rdbmstool.DECIMAL,
+		Length: 10, DecimalPrecision: 2}
+
+	dvDef := definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Customer", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "Name"}}},
+			definition.HubDefinition{Name: "Invoice", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "InvoiceNo"}}}}}
+	refDef := definition.ReferenceDefinition{
+		Name:       "Currency",
+		Keys:       []string{"CurrencyCode"},
+		Attributes: []definition.SateliteAttributeDefinition{currencyCode, description}}
+	nhlDef := definition.NonHistorizedLinkDefinition{
+		Name: "Payment",
+		HubReferences: []definition.HubReference{
+			definition.HubReference{HubName: "Customer"}, definition.HubReference{HubName: "Invoice"}},
+		Attributes: []definition.SateliteAttributeDefinition{paymentNo, amount}}
+
+	dvtest.CreateSchema(t, dv.Db, dv.Dialect, dvDef, refDef.GenerateDialectSQL, nhlDef.GenerateDialectSQL)
+
+	businessKeys := func(key string, value string) []record.HubBusinessKeyInsertRecord {
+		return []record.HubBusinessKeyInsertRecord{
+			record.HubBusinessKeyInsertRecord{BusinessKey: key, BusinessValue: value}}
+	}
+	createRecord := func(loadDate time.Time, payment string, value float64) *record.DvInsertRecord {
+		return &record.DvInsertRecord{
+			SkipExistingKeys: true,
+			References: []record.ReferenceInsertRecord{
+				record.ReferenceInsertRecord{ReferenceName: "Currency", RecordSource: "erp", LoadDate: loadDate,
+					Keys: []string{"CurrencyCode"},
+					Attributes: []record.SateliteAttrInsertRecord{
+						record.SateliteAttrInsertRecord{AttributeName: "CurrencyCode", Value: "MYR", Meta: &currencyCode},
+						record.SateliteAttrInsertRecord{AttributeName: "Description", Value: "Ringgit", Meta: &description}}}},
+			Hubs: []record.HubInsertRecord{
+				record.HubInsertRecord{HubName: "Customer", RecordSource: "erp", LoadDate: loadDate,
+					BusinessKeyVues: businessKeys("Name", "O'Brien")},
+				record.HubInsertRecord{HubName: "Invoice", RecordSource: "erp", LoadDate: loadDate,
+					BusinessKeyVues: businessKeys("InvoiceNo", "INV-1")}},
+			NonHistorizedLinks: []record.NonHistorizedLinkInsertRecord{
+				record.NonHistorizedLinkInsertRecord{LinkName: "Payment", RecordSource: "erp", LoadDate: loadDate,
+					DependentKeys: []string{"PaymentNo"},
+					ReferenceHashKey: []record.LinkReferenceInsertRecord{
+						record.LinkReferenceInsertRecord{HubName: "Customer",
+							BusinessKeyValues: businessKeys("Name", "O'Brien")},
+						record.LinkReferenceInsertRecord{HubName: "Invoice",
+							BusinessKeyValues: businessKeys("InvoiceNo", "INV-1")}},
+					Attributes: []record.SateliteAttrInsertRecord{
+						record.SateliteAttrInsertRecord{AttributeName: "PaymentNo", Value: payment, Meta: &paymentNo},
+						record.SateliteAttrInsertRecord{AttributeName: "Amount", Value: value, Meta: &amount}}}}}
+	}
+
+	day1 := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+	for index, payment := range []string{"PAY-1", "PAY-1", "PAY-2"} {
+		if insertErr := dv.InsertRecord(createRecord(day1.AddDate(0, 0, index), payment, 50.5)); insertErr != nil {
+			t.Fatal(insertErr.Error())
+		}
+	}
+
+	var refCount, nhlCount int
+	dv.Db.QueryRow("SELECT COUNT(*) FROM ref_currency_rev0").Scan(&refCount)
+	dv.Db.QueryRow("SELECT COUNT(*) FROM nhl_payment_rev0").Scan(&nhlCount)
+
+	if refCount != 1 || nhlCount != 2 {
+		t.Errorf("Expect 1 currency and 2 payment rows, given %d and %d instead", refCount, nhlCount)
+	}
+
+	readRef, refErr := dv.MetaReader.GetReferenceDefinition("Currency", 0, dv.Db)
+	if refErr != nil {
+		t.Fatal(refErr.Error())
+	}
+	if len(readRef.Keys) != 1 || readRef.Keys[0] != "CurrencyCode" || len(readRef.Attributes) != 2 {
+		t.Errorf("Expect currency reference keyed by CurrencyCode, given %v instead", readRef)
+	}
+
+	relationship, relErr := dv.MetaReader.GetRelationship(dv.Db, "Invoice", 0)
+	if relErr != nil {
+		t.Fatal(relErr.Error())
+	}
+	if len(relationship.NonHistorizedLinks) != 1 || len(relationship.NonHistorizedLinks[0].HubReferences) != 2 ||
+		len(relationship.NonHistorizedLinks[0].Attributes) != 2 {
+		t.Errorf("Expect payment non-historized link is resolved, given %v instead", relationship.NonHistorizedLinks)
+	}
+}
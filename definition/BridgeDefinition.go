@@ -0,0 +1,95 @@
+package definition
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+)
+
+//BridgeDefinition is schema to describe bridge table; it start from a hub and walk
+//through each path step (a link then the hub it lead to) while keeping every
+//hash key along the path per snapshot date
+type BridgeDefinition struct {
+	Name          string
+	Revision      int
+	HubReference  *HubReference
+	Path          []BridgeStep
+	HashAlgorithm hashkey.Algorithm
+}
+
+//BridgeStep is one step of bridge path, from previous hub through link into hub
+type BridgeStep struct {
+	LinkReference LinkReference
+	HubReference  HubReference
+}
+
+//GetDbTableName is to generate equivalent data table name
+func (bridgeDef *BridgeDefinition) GetDbTableName() string {
+	return BridgeTableName(bridgeDef.Name, bridgeDef.Revision)
+}
+
+//Validate is to verify bridge has start hub and path, and hash key columns along the path are unique
+func (bridgeDef *BridgeDefinition) Validate() error {
+	if bridgeDef == nil {
+		return errors.New("Input parameter cannot be null")
+	}
+
+	if bridgeDef.HubReference == nil {
+		return errors.New("Bridge has no hub reference")
+	}
+
+	if bridgeDef.Path == nil || len(bridgeDef.Path) == 0 {
+		return errors.New("Bridge must has atleast one path step")
+	}
+
+	columns := map[string]bool{bridgeDef.HubReference.GetHashKey(): true}
+	for _, step := range bridgeDef.Path {
+		for _, column := range []string{step.LinkReference.GetHashKey(), step.HubReference.GetHashKey()} {
+			if columns[column] {
+				return fmt.Errorf("Bridge path has duplicate hash key column %s", column)
+			}
+			columns[column] = true
+		}
+	}
+
+	return nil
+}
+
+// GenerateSQL is to generate SQL statement based on bridge definition
+func (bridgeDef *BridgeDefinition) GenerateSQL() (string, error) {
+	return bridgeDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on bridge definition for given SQL dialect
+func (bridgeDef *BridgeDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if err := bridgeDef.Validate(); err != nil {
+		return "", err
+	}
+
+	//bridge is derived from links, so it has no foreign key and
+	//can be dropped and rebuilt any time
+	tableDef := rdbmstool.TableDefinition{
+		Name: bridgeDef.GetDbTableName(),
+		Columns: []rdbmstool.ColumnDefinition{
+			createSnapshotDateColumn(),
+			createHashKeyColumn(bridgeDef.HubReference.HubName, bridgeDef.HashAlgorithm)},
+		PrimaryKey:  []string{SNAPSHOT_DATE},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
+		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
+		Indices: []rdbmstool.IndexKeyDefinition{
+			createIndexKey(bridgeDef.HubReference.GetHashKey())}}
+
+	for _, step := range bridgeDef.Path {
+		tableDef.Columns = append(tableDef.Columns,
+			createHashKeyColumn(step.LinkReference.LinkName, bridgeDef.HashAlgorithm),
+			createHashKeyColumn(step.HubReference.HubName, bridgeDef.HashAlgorithm))
+
+		//path is identified by link hash keys
+		tableDef.PrimaryKey = append(tableDef.PrimaryKey, step.LinkReference.GetHashKey())
+	}
+
+	return sqlDialect.CreateTableSQL(&tableDef)
+}
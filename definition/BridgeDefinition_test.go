@@ -0,0 +1,37 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/dialect"
+)
+
+func TestBridgeDefinitionGenerateSQL(t *testing.T) {
+	bridgeDef := BridgeDefinition{
+		Name:         "InvoiceProduct",
+		HubReference: &HubReference{HubName: "Invoice"},
+		Path: []BridgeStep{
+			BridgeStep{
+				LinkReference: LinkReference{LinkName: "InvoiceOrderItem"},
+				HubReference:  HubReference{HubName: "OrderItem"}},
+			BridgeStep{
+				LinkReference: LinkReference{LinkName: "OrderItemProduct"},
+				HubReference:  HubReference{HubName: "Product"}}}}
+
+	sql, err := bridgeDef.GenerateDialectSQL(dialect.SQLITE)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !strings.Contains(sql, "bridge_invoice_product_rev0") ||
+		!strings.Contains(sql, "order_item_product_hash_key") {
+		t.Errorf("Expect bridge table with path hash keys, given %s instead", sql)
+	}
+
+	bridgeDef.Path[1].HubReference = HubReference{HubName: "Invoice"}
+	if _, dupErr := bridgeDef.GenerateSQL(); dupErr == nil {
+		t.Error("Expect error for duplicate hash key column along bridge path")
+	}
+}
@@ -1,6 +1,10 @@
 package definition
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/hashkey"
 	"github.com/guinso/rdbmstool"
 	"github.com/guinso/stringtool"
 )
@@ -12,12 +16,26 @@ const (
 	END_DATE = "end_date"
 	//RECORD_SOURCE is data vault standard table column name
 	RECORD_SOURCE = "record_source"
+	//HASH_DIFF is data vault standard table column name
+	HASH_DIFF = "hash_diff"
+	//START_DATE is data vault standard table column name
+	START_DATE = "start_date"
+	//SNAPSHOT_DATE is data vault standard table column name
+	SNAPSHOT_DATE = "snapshot_date"
 )
 
-func createHashKeyColumn(name string) rdbmstool.ColumnDefinition {
+//createHashKeyColumn is to create hash key column wide enough to hold hash key of given
+//algorithm; MD5 (CHAR 32) is used if algorithm is not specified
+func createHashKeyColumn(name string, algorithm hashkey.Algorithm) rdbmstool.ColumnDefinition {
 	return rdbmstool.ColumnDefinition{
-		Name:     stringtool.ToSnakeCase(name) + "_hash_key",
-		DataType: rdbmstool.CHAR, Length: 32, IsNullable: false}
+		Name:     HashKeyColumnName(name),
+		DataType: rdbmstool.CHAR, Length: algorithm.Length(), IsNullable: false}
+}
+
+func createRoleHashKeyColumn(role string, hubName string, algorithm hashkey.Algorithm) rdbmstool.ColumnDefinition {
+	return rdbmstool.ColumnDefinition{
+		Name:     RoleHashKeyColumnName(role, hubName),
+		DataType: rdbmstool.CHAR, Length: algorithm.Length(), IsNullable: false}
 }
 
 func createEndDateColumn() rdbmstool.ColumnDefinition {
@@ -25,6 +43,11 @@ func createEndDateColumn() rdbmstool.ColumnDefinition {
 		DataType: rdbmstool.DATETIME, Length: 0, IsNullable: true}
 }
 
+func createStartDateColumn() rdbmstool.ColumnDefinition {
+	return rdbmstool.ColumnDefinition{Name: START_DATE,
+		DataType: rdbmstool.DATETIME, Length: 0, IsNullable: false}
+}
+
 func createLoadDateColumn() rdbmstool.ColumnDefinition {
 	return rdbmstool.ColumnDefinition{Name: LOAD_DATE,
 		DataType: rdbmstool.DATETIME, Length: 0, IsNullable: false}
@@ -35,6 +58,48 @@ func createRecordSourceColumn() rdbmstool.ColumnDefinition {
 		DataType: rdbmstool.CHAR, Length: 100, IsNullable: false}
 }
 
+func createHashDiffColumn(algorithm hashkey.Algorithm) rdbmstool.ColumnDefinition {
+	return rdbmstool.ColumnDefinition{Name: HASH_DIFF,
+		DataType: rdbmstool.CHAR, Length: algorithm.Length(), IsNullable: false}
+}
+
+func createSnapshotDateColumn() rdbmstool.ColumnDefinition {
+	return rdbmstool.ColumnDefinition{Name: SNAPSHOT_DATE,
+		DataType: rdbmstool.DATETIME, Length: 0, IsNullable: false}
+}
+
+func createAttributeColumn(attribute SateliteAttributeDefinition) rdbmstool.ColumnDefinition {
+	return attribute.GetAttributeColumn()
+}
+
+//getKeyAttributeColumnNames is to get column name of key attributes; each key must
+//refer to a non-nullable attribute which is not TEXT (it is part of primary key)
+func getKeyAttributeColumnNames(attributes []SateliteAttributeDefinition, keys []string) ([]string, error) {
+	colNames := []string{}
+	for _, key := range keys {
+		var keyAttr *SateliteAttributeDefinition
+		for index := range attributes {
+			if strings.Compare(stringtool.ToSnakeCase(attributes[index].Name),
+				stringtool.ToSnakeCase(key)) == 0 {
+				keyAttr = &attributes[index]
+				break
+			}
+		}
+
+		if keyAttr == nil {
+			return nil, fmt.Errorf("%s not found in attributes", key)
+		}
+
+		if keyAttr.IsNullable || keyAttr.DataType == rdbmstool.TEXT {
+			return nil, fmt.Errorf("%s must be non-nullable and not TEXT", key)
+		}
+
+		colNames = append(colNames, stringtool.ToSnakeCase(key))
+	}
+
+	return colNames, nil
+}
+
 func createIndexKey(colName string) rdbmstool.IndexKeyDefinition {
 	return rdbmstool.IndexKeyDefinition{ColumnNames: []string{colName}}
 }
@@ -0,0 +1,62 @@
+package definition
+
+import (
+	"fmt"
+
+	"github.com/guinso/stringtool"
+)
+
+//HubTableName is data table name of hub entity
+func HubTableName(hubName string, revision int) string {
+	return fmt.Sprintf("hub_%s_rev%d", stringtool.ToSnakeCase(hubName), revision)
+}
+
+//LinkTableName is data table name of link entity
+func LinkTableName(linkName string, revision int) string {
+	return fmt.Sprintf("link_%s_rev%d", stringtool.ToSnakeCase(linkName), revision)
+}
+
+//SateliteTableName is data table name of satelite entity
+func SateliteTableName(satName string, revision int) string {
+	return fmt.Sprintf("sat_%s_rev%d", stringtool.ToSnakeCase(satName), revision)
+}
+
+//EffectivitySateliteTableName is data table name of effectivity satelite entity
+func EffectivitySateliteTableName(satName string, revision int) string {
+	return fmt.Sprintf("esat_%s_rev%d", stringtool.ToSnakeCase(satName), revision)
+}
+
+//ReferenceTableName is data table name of reference table entity
+func ReferenceTableName(refName string, revision int) string {
+	return fmt.Sprintf("ref_%s_rev%d", stringtool.ToSnakeCase(refName), revision)
+}
+
+//NonHistorizedLinkTableName is data table name of non-historized link entity
+func NonHistorizedLinkTableName(linkName string, revision int) string {
+	return fmt.Sprintf("nhl_%s_rev%d", stringtool.ToSnakeCase(linkName), revision)
+}
+
+//PitTableName is data table name of point in time (PIT) table
+func PitTableName(pitName string, revision int) string {
+	return fmt.Sprintf("pit_%s_rev%d", stringtool.ToSnakeCase(pitName), revision)
+}
+
+//BridgeTableName is data table name of bridge table
+func BridgeTableName(bridgeName string, revision int) string {
+	return fmt.Sprintf("bridge_%s_rev%d", stringtool.ToSnakeCase(bridgeName), revision)
+}
+
+//HashKeyColumnName is hash key column name of hub or link entity
+func HashKeyColumnName(entityName string) string {
+	return fmt.Sprintf("%s_hash_key", stringtool.ToSnakeCase(entityName))
+}
+
+//RoleHashKeyColumnName is hash key column name of role-named hub reference,
+//example: manager_employee_hash_key; it is same as HashKeyColumnName if role is empty
+func RoleHashKeyColumnName(role string, hubName string) string {
+	if role == "" {
+		return HashKeyColumnName(hubName)
+	}
+
+	return fmt.Sprintf("%s_%s", stringtool.ToSnakeCase(role), HashKeyColumnName(hubName))
+}
@@ -1,10 +1,18 @@
 package definition
 
-//DataVaultDefinition is a set of DataVault definition (blue print) to build data vault's database
+import (
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+)
+
+//DataVaultDefinition is a set of DataVault definition (blue print) to build data vault's database;
+//HashAlgorithm is optional, if specified it overrides hash algorithm of every entity so hash key
+//columns are wide enough for hash key computed by data vault's hasher
 type DataVaultDefinition struct {
-	Hubs      []HubDefinition
-	satelites []SateliteDefinition
-	Links     []LinkDefinition
+	Hubs          []HubDefinition
+	Satelites     []SateliteDefinition
+	Links         []LinkDefinition
+	HashAlgorithm hashkey.Algorithm
 }
 
 //EntityType data vault entity category; e.g. hub, link, and satelite
@@ -15,6 +23,12 @@ const (
 	HUB      EntityType = iota + 1
 	LINK     EntityType = iota + 1
 	SATELITE EntityType = iota + 1
+	//EFFECTIVITY_SATELITE is satelite which record validity period of link
+	EFFECTIVITY_SATELITE EntityType = iota + 1
+	//REFERENCE is reference table of code list, e.g. currency or country
+	REFERENCE EntityType = iota + 1
+	//NON_HISTORIZED_LINK is link which carry immutable transaction attributes
+	NON_HISTORIZED_LINK EntityType = iota + 1
 )
 
 func (entity EntityType) String() string {
@@ -24,6 +38,12 @@ func (entity EntityType) String() string {
 		return "link"
 	} else if entity == SATELITE {
 		return "satelite"
+	} else if entity == EFFECTIVITY_SATELITE {
+		return "effectivity satelite"
+	} else if entity == REFERENCE {
+		return "reference"
+	} else if entity == NON_HISTORIZED_LINK {
+		return "non-historized link"
 	}
 
 	return "unknown"
@@ -31,12 +51,24 @@ func (entity EntityType) String() string {
 
 //GenerateSQL is to generate multiple SQL statements to create respective DV data tables
 func (dvDef *DataVaultDefinition) GenerateSQL() ([]string, error) {
+	return dvDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+//GenerateDialectSQL is to generate multiple SQL statements to create respective DV data tables
+//for given SQL dialect; links are created before satelites as satelite may refer to link;
+//definition is not validated since entity may refer to hub or link which already exists in
+//database, call Validate beforehand if definition is complete
+func (dvDef *DataVaultDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) ([]string, error) {
 	result := []string{}
 
 	//generate Hubs' SQL
 	if len(dvDef.Hubs) > 0 {
 		for _, hubDef := range dvDef.Hubs {
-			hubSQL, hubErr := hubDef.GenerateSQL()
+			if dvDef.HashAlgorithm > 0 {
+				hubDef.HashAlgorithm = dvDef.HashAlgorithm
+			}
+
+			hubSQL, hubErr := hubDef.GenerateDialectSQL(sqlDialect)
 
 			if hubErr != nil {
 				return nil, hubErr
@@ -46,10 +78,31 @@ func (dvDef *DataVaultDefinition) GenerateSQL() ([]string, error) {
 		}
 	}
 
+	//generate Links' SQL
+	if len(dvDef.Links) > 0 {
+		for _, linkDef := range dvDef.Links {
+			if dvDef.HashAlgorithm > 0 {
+				linkDef.HashAlgorithm = dvDef.HashAlgorithm
+			}
+
+			linkSQL, linkErr := linkDef.GenerateDialectSQL(sqlDialect)
+
+			if linkErr != nil {
+				return nil, linkErr
+			}
+
+			result = append(result, linkSQL)
+		}
+	}
+
 	//generate Satelites' SQL
-	if len(dvDef.satelites) > 0 {
-		for _, satDef := range dvDef.satelites {
-			satSQL, satErr := satDef.GenerateSQL()
+	if len(dvDef.Satelites) > 0 {
+		for _, satDef := range dvDef.Satelites {
+			if dvDef.HashAlgorithm > 0 {
+				satDef.HashAlgorithm = dvDef.HashAlgorithm
+			}
+
+			satSQL, satErr := satDef.GenerateDialectSQL(sqlDialect)
 
 			if satErr != nil {
 				return nil, satErr
@@ -59,18 +112,80 @@ func (dvDef *DataVaultDefinition) GenerateSQL() ([]string, error) {
 		}
 	}
 
-	//generate Links' SQL
-	if len(dvDef.Links) > 0 {
-		for _, linkDef := range dvDef.Links {
-			linkSQL, linkErr := linkDef.GenerateSQL()
+	return result, nil
+}
 
-			if linkErr != nil {
-				return nil, linkErr
+//Validate is to check data vault definition is consistent before any data table is created:
+//entity name is unique per revision, every hub reference of link and satelite resolves to hub
+//in this definition, link has atleast two hub references, and satelite has exactly one parent;
+//all problems found are reported at once as *ValidationError
+func (dvDef *DataVaultDefinition) Validate() error {
+	validationErr := ValidationError{}
+
+	hubs := make(map[string]bool)
+	for _, hubDef := range dvDef.Hubs {
+		if hubDef.Name == "" {
+			validationErr.add("hub must has a name")
+			continue
+		}
+
+		if hubs[hubDef.GetDbTableName()] {
+			validationErr.add("hub %s revision %d is defined more than once", hubDef.Name, hubDef.Revision)
+		}
+		hubs[hubDef.GetDbTableName()] = true
+	}
+
+	links := make(map[string]bool)
+	for _, linkDef := range dvDef.Links {
+		if linkDef.Name == "" {
+			validationErr.add("link must has a name")
+			continue
+		}
+
+		if links[linkDef.GetDbTableName()] {
+			validationErr.add("link %s revision %d is defined more than once", linkDef.Name, linkDef.Revision)
+		}
+		links[linkDef.GetDbTableName()] = true
+
+		if len(linkDef.HubReferences) < 2 {
+			validationErr.add("link %s must has atleast two hub references, given %d",
+				linkDef.Name, len(linkDef.HubReferences))
+		}
+
+		for _, hubRef := range linkDef.HubReferences {
+			if !hubs[hubRef.GetDbTableName()] {
+				validationErr.add("link %s refers to hub %s revision %d which is not defined",
+					linkDef.Name, hubRef.HubName, hubRef.Revision)
 			}
+		}
+	}
 
-			result = append(result, linkSQL)
+	satelites := make(map[string]bool)
+	for _, satDef := range dvDef.Satelites {
+		if satDef.Name == "" {
+			validationErr.add("satelite must has a name")
+			continue
+		}
+
+		if satelites[satDef.GetDbTableName()] {
+			validationErr.add("satelite %s revision %d is defined more than once", satDef.Name, satDef.Revision)
+		}
+		satelites[satDef.GetDbTableName()] = true
+
+		if (satDef.HubReference == nil) == (satDef.LinkReference == nil) {
+			validationErr.add("satelite %s must refer to either one hub or one link", satDef.Name)
+		} else if satDef.HubReference != nil && !hubs[satDef.HubReference.GetDbTableName()] {
+			validationErr.add("satelite %s refers to hub %s revision %d which is not defined",
+				satDef.Name, satDef.HubReference.HubName, satDef.HubReference.Revision)
+		} else if satDef.LinkReference != nil && !links[satDef.LinkReference.GetDbTableName()] {
+			validationErr.add("satelite %s refers to link %s revision %d which is not defined",
+				satDef.Name, satDef.LinkReference.LinkName, satDef.LinkReference.Revision)
 		}
 	}
 
-	return result, nil
+	if len(validationErr.Problems) > 0 {
+		return &validationErr
+	}
+
+	return nil
 }
@@ -1,8 +1,10 @@
 package definition
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/guinso/datavault/dialect"
 	"github.com/guinso/rdbmstool"
 )
 
@@ -10,11 +12,14 @@ func TestDataVaultDefinitionGenerateSQL(t *testing.T) {
 	dvDef := DataVaultDefinition{
 		Hubs: []HubDefinition{
 			HubDefinition{
-				Name: "invoice",
-				BusinessKeys: []string{
-					"docNo"},
-				Revision: 0}},
-		satelites: []SateliteDefinition{
+				Name:         "invoice",
+				BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "docNo"}},
+				Revision:     0},
+			HubDefinition{
+				Name:         "employee",
+				BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "employeeNo"}},
+				Revision:     0}},
+		Satelites: []SateliteDefinition{
 			SateliteDefinition{
 				Name: "invoice",
 				HubReference: &HubReference{
@@ -55,3 +60,162 @@ func TestDataVaultDefinitionGenerateSQL(t *testing.T) {
 		return
 	}
 }
+
+func TestDataVaultDefinitionGeneratePostgresSQL(t *testing.T) {
+	dvDef := DataVaultDefinition{
+		Hubs: []HubDefinition{
+			HubDefinition{
+				Name:         "Invoice",
+				BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "InvoiceNo"}},
+				Revision:     0}},
+		Satelites: []SateliteDefinition{
+			SateliteDefinition{
+				Name: "Invoice",
+				HubReference: &HubReference{
+					HubName:  "Invoice",
+					Revision: 0},
+				Revision: 0,
+				Attributes: []SateliteAttributeDefinition{
+					SateliteAttributeDefinition{
+						Name:     "DateOfIssue",
+						DataType: rdbmstool.DATETIME},
+					SateliteAttributeDefinition{
+						Name:             "Tax",
+						DataType:         rdbmstool.DECIMAL,
+						Length:           10,
+						DecimalPrecision: 2}}}}}
+
+	sqls, err := dvDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(sqls) != 2 {
+		t.Errorf("Expect 2 SQL statements, given %d instead", len(sqls))
+		return
+	}
+
+	for _, expected := range []string{
+		"CREATE TABLE \"sat_invoice_rev0\"",
+		"\"date_of_issue\" TIMESTAMP NOT NULL",
+		"\"tax\" NUMERIC(10,2) NOT NULL",
+		"PRIMARY KEY (\"invoice_hash_key\", \"load_date\")",
+		"FOREIGN KEY (\"invoice_hash_key\") REFERENCES \"hub_invoice_rev0\" (\"invoice_hash_key\")"} {
+		if !strings.Contains(sqls[1], expected) {
+			t.Errorf("Expect SQL statement contains %s:\n%s", expected, sqls[1])
+		}
+	}
+}
+
+func TestDataVaultDefinitionValidate(t *testing.T) {
+	dvDef := DataVaultDefinition{
+		Hubs: []HubDefinition{
+			HubDefinition{Name: "Invoice", BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "InvoiceNo"}}},
+			HubDefinition{Name: "Invoice", BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "DocNo"}}},
+			HubDefinition{Name: "Invoice", Revision: 1, BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "DocNo"}}}},
+		Satelites: []SateliteDefinition{
+			SateliteDefinition{Name: "Customer", HubReference: &HubReference{HubName: "Customer"}},
+			SateliteDefinition{Name: "InvoiceEmployee", LinkReference: &LinkReference{LinkName: "InvoiceEmployee"}}},
+		Links: []LinkDefinition{
+			LinkDefinition{Name: "InvoiceEmployee", HubReferences: []HubReference{
+				HubReference{HubName: "Invoice"}, HubReference{HubName: "Employee"}}},
+			LinkDefinition{Name: "InvoiceOnly", HubReferences: []HubReference{
+				HubReference{HubName: "Invoice"}}}}}
+
+	err := dvDef.Validate()
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expect validation error, given %v instead", err)
+	}
+
+	for _, expected := range []string{
+		"hub Invoice revision 0 is defined more than once",
+		"link InvoiceEmployee refers to hub Employee revision 0 which is not defined",
+		"link InvoiceOnly must has atleast two hub references, given 1",
+		"satelite Customer refers to hub Customer revision 0 which is not defined"} {
+		found := false
+		for _, problem := range validationErr.Problems {
+			if problem == expected {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("Expect problem %s, given %v instead", expected, validationErr.Problems)
+		}
+	}
+
+	if len(validationErr.Problems) != 4 {
+		t.Errorf("Expect 4 problems, given %v instead", validationErr.Problems)
+	}
+}
+
+func TestDataVaultDefinitionGeneratePartialSQL(t *testing.T) {
+	//satelite of hub which already exists in database, so hub is not part of definition
+	dvDef := DataVaultDefinition{
+		Satelites: []SateliteDefinition{
+			SateliteDefinition{
+				Name:         "CustomerAddress",
+				HubReference: &HubReference{HubName: "Customer"},
+				Attributes: []SateliteAttributeDefinition{
+					SateliteAttributeDefinition{Name: "Street", DataType: rdbmstool.TEXT}}}}}
+
+	if dvDef.Validate() == nil {
+		t.Error("Expect validation error for satelite refer to hub which is not defined")
+	}
+
+	sqls, err := dvDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(sqls) != 1 || !strings.Contains(sqls[0],
+		"REFERENCES \"hub_customer_rev0\" (\"customer_hash_key\")") {
+		t.Errorf("Expect satelite refer to existing hub, given %v instead", sqls)
+	}
+}
+
+func TestDataVaultDefinitionGenerateSQLOrder(t *testing.T) {
+	dvDef := DataVaultDefinition{
+		Hubs: []HubDefinition{
+			HubDefinition{Name: "invoice", BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "docNo"}}},
+			HubDefinition{Name: "employee", BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "staffNo"}}}},
+		Satelites: []SateliteDefinition{
+			SateliteDefinition{
+				Name:          "invPreparedBy",
+				LinkReference: &LinkReference{LinkName: "invPreparedBy"},
+				Attributes: []SateliteAttributeDefinition{
+					SateliteAttributeDefinition{
+						Name:     "remark",
+						DataType: rdbmstool.TEXT}}}},
+		Links: []LinkDefinition{
+			LinkDefinition{
+				Name: "invPreparedBy",
+				HubReferences: []HubReference{
+					HubReference{HubName: "invoice"},
+					HubReference{HubName: "employee"}}}}}
+
+	for _, sqlDialect := range []dialect.Dialect{dialect.POSTGRES, dialect.SQLITE} {
+		sqls, err := dvDef.GenerateDialectSQL(sqlDialect)
+		if err != nil {
+			t.Error(err.Error())
+			return
+		}
+
+		if len(sqls) != 4 {
+			t.Errorf("Expect 4 SQL statements, given %d instead", len(sqls))
+			return
+		}
+
+		//every table must be created after the tables it refers to
+		for index, tableName := range []string{
+			"hub_invoice_rev0", "hub_employee_rev0", "link_inv_prepared_by_rev0", "sat_inv_prepared_by_rev0"} {
+			if !strings.Contains(sqls[index], "CREATE TABLE "+sqlDialect.QuoteIdentifier(tableName)) {
+				t.Errorf("Expect statement %d creates table %s:\n%s", index, tableName, sqls[index])
+			}
+		}
+	}
+}
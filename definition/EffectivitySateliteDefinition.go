@@ -0,0 +1,132 @@
+package definition
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+)
+
+//EffectivitySateliteDefinition is schema to describe effectivity satelite which record
+//validity period (start date and end date) of link relationship;
+//DrivingKey is hub of link which only has one active partner at a time,
+//e.g. invoice of invoice-employee link where invoice is prepared by one employee at a time;
+//Role of DrivingKey is required if link refers to the same hub more than once,
+//e.g. subordinate of hierarchical employee link which only has one manager at a time
+type EffectivitySateliteDefinition struct {
+	Name          string
+	Revision      int
+	LinkReference *LinkReference
+	DrivingKey    *HubReference
+	HashAlgorithm hashkey.Algorithm
+}
+
+//GetDbTableName is function to generate equivalence datatable name
+func (esatDef *EffectivitySateliteDefinition) GetDbTableName() string {
+	return EffectivitySateliteTableName(esatDef.Name, esatDef.Revision)
+}
+
+//Validate is to verify effectivity satelite refers to given link and its driving key
+//resolves to exactly one hub reference (hub and role) of the link
+func (esatDef *EffectivitySateliteDefinition) Validate(linkDef *LinkDefinition) error {
+	if esatDef == nil || linkDef == nil {
+		return errors.New("Input parameter cannot be null")
+	}
+
+	if esatDef.LinkReference == nil {
+		return errors.New("Effectivity satelite has no link reference")
+	}
+
+	if esatDef.DrivingKey == nil {
+		return errors.New("Effectivity satelite has no driving key")
+	}
+
+	if strings.Compare(esatDef.LinkReference.GetDbTableName(), linkDef.GetDbTableName()) != 0 {
+		return fmt.Errorf("Effectivity satelite %s refers to link %s revision %d but given link %s revision %d",
+			esatDef.Name, esatDef.LinkReference.LinkName, esatDef.LinkReference.Revision,
+			linkDef.Name, linkDef.Revision)
+	}
+
+	sameHubCount := 0
+	for _, hubRef := range linkDef.HubReferences {
+		if strings.Compare(hubRef.GetDbTableName(), esatDef.DrivingKey.GetDbTableName()) != 0 {
+			continue
+		}
+
+		if strings.Compare(hubRef.GetColumnName(), esatDef.DrivingKey.GetColumnName()) == 0 {
+			return nil
+		}
+		sameHubCount++
+	}
+
+	if sameHubCount > 1 {
+		return fmt.Errorf("Effectivity satelite %s driving key hub %s is ambiguous, "+
+			"link %s refers to it %d times; driving key role is required",
+			esatDef.Name, esatDef.DrivingKey.HubName, linkDef.Name, sameHubCount)
+	}
+
+	return fmt.Errorf("Effectivity satelite %s driving key %s is not referred by link %s",
+		esatDef.Name, esatDef.DrivingKey.GetColumnName(), linkDef.Name)
+}
+
+// GenerateSQL is to generate SQL statement based on effectivity satelite definition
+func (esatDef *EffectivitySateliteDefinition) GenerateSQL() (string, error) {
+	return esatDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on effectivity satelite definition
+// for given SQL dialect
+func (esatDef *EffectivitySateliteDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if esatDef == nil {
+		return "", errors.New("Input parameter cannot be null")
+	}
+
+	if esatDef.LinkReference == nil {
+		return "", errors.New("Effectivity satelite has no link reference")
+	}
+
+	if esatDef.DrivingKey == nil {
+		return "", errors.New("Effectivity satelite has no driving key")
+	}
+
+	linkHashKey := esatDef.LinkReference.GetHashKey()
+	drivingColumn := esatDef.DrivingKey.GetColumnName()
+
+	tableDef := rdbmstool.TableDefinition{
+		Name: esatDef.GetDbTableName(),
+		Columns: []rdbmstool.ColumnDefinition{
+			createHashKeyColumn(esatDef.LinkReference.LinkName, esatDef.HashAlgorithm),
+			createRoleHashKeyColumn(esatDef.DrivingKey.Role, esatDef.DrivingKey.HubName, esatDef.HashAlgorithm),
+			createLoadDateColumn(),
+			createStartDateColumn(),
+			createEndDateColumn(),
+			createRecordSourceColumn()},
+		PrimaryKey: []string{linkHashKey, LOAD_DATE},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{
+			rdbmstool.ForeignKeyDefinition{
+				ReferenceTableName: esatDef.LinkReference.GetDbTableName(),
+				Columns: []rdbmstool.FKColumnDefinition{
+					rdbmstool.FKColumnDefinition{
+						ColumnName:    linkHashKey,
+						RefColumnName: linkHashKey}}},
+			rdbmstool.ForeignKeyDefinition{
+				ReferenceTableName: esatDef.DrivingKey.GetDbTableName(),
+				Columns: []rdbmstool.FKColumnDefinition{
+					rdbmstool.FKColumnDefinition{
+						ColumnName:    drivingColumn,
+						RefColumnName: esatDef.DrivingKey.GetHashKey()}}}},
+		UniqueKeys: []rdbmstool.UniqueKeyDefinition{},
+		Indices: []rdbmstool.IndexKeyDefinition{
+			createIndexKey(linkHashKey),
+			createIndexKey(drivingColumn)}}
+
+	sql, err := sqlDialect.CreateTableSQL(&tableDef)
+	if err != nil {
+		return "", err
+	}
+
+	return sql, nil
+}
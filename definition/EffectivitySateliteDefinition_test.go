@@ -0,0 +1,77 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/dialect"
+)
+
+func TestEffectivitySateliteGenerateSQL(t *testing.T) {
+	esatDef := EffectivitySateliteDefinition{
+		Name:          "InvoiceEmployee",
+		Revision:      0,
+		LinkReference: &LinkReference{LinkName: "InvoiceEmployee", Revision: 0},
+		DrivingKey:    &HubReference{HubName: "Invoice", Revision: 0}}
+
+	sql, err := esatDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"CREATE TABLE \"esat_invoice_employee_rev0\"",
+		"\"start_date\" TIMESTAMP NOT NULL",
+		"PRIMARY KEY (\"invoice_employee_hash_key\", \"load_date\")",
+		"REFERENCES \"link_invoice_employee_rev0\"",
+		"REFERENCES \"hub_invoice_rev0\""} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+
+	if _, noKeyErr := (&EffectivitySateliteDefinition{Name: "InvoiceEmployee",
+		LinkReference: &LinkReference{LinkName: "InvoiceEmployee"}}).GenerateSQL(); noKeyErr == nil {
+		t.Error("Expect error for effectivity satelite without driving key")
+	}
+}
+
+func TestEffectivitySateliteRoleDrivingKey(t *testing.T) {
+	linkDef := LinkDefinition{
+		Name: "EmployeeHierarchy",
+		HubReferences: []HubReference{
+			HubReference{HubName: "Employee", Role: "Manager"},
+			HubReference{HubName: "Employee", Role: "Subordinate"}}}
+	esatDef := EffectivitySateliteDefinition{
+		Name:          "EmployeeHierarchy",
+		LinkReference: &LinkReference{LinkName: "EmployeeHierarchy"},
+		DrivingKey:    &HubReference{HubName: "Employee"}}
+
+	if err := esatDef.Validate(&linkDef); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("Expect error for ambiguous driving key, given %v instead", err)
+	}
+
+	esatDef.DrivingKey.Role = "Supervisor"
+	if err := esatDef.Validate(&linkDef); err == nil {
+		t.Error("Expect error for driving key role which is not referred by link")
+	}
+
+	esatDef.DrivingKey.Role = "Subordinate"
+	if err := esatDef.Validate(&linkDef); err != nil {
+		t.Error(err.Error())
+	}
+
+	sql, err := esatDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, expected := range []string{
+		"\"subordinate_employee_hash_key\" CHAR(32) NOT NULL",
+		"FOREIGN KEY (\"subordinate_employee_hash_key\") REFERENCES \"hub_employee_rev0\" (\"employee_hash_key\")"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+}
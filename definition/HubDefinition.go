@@ -1,33 +1,82 @@
 package definition
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
 	"github.com/guinso/rdbmstool"
 	"github.com/guinso/stringtool"
 )
 
 //HubDefinition is schema to descibe hub structure
 type HubDefinition struct {
-	Name         string
-	BusinessKeys []string
-	Revision     int
+	Name          string
+	BusinessKeys  []BusinessKeyDefinition
+	Revision      int
+	HashAlgorithm hashkey.Algorithm
+}
+
+//BusinessKeyDefinition is schema to describe hub business key column;
+//DataType is CHAR(100) if not specified, length of CHAR and VARCHAR is 100 if not specified
+type BusinessKeyDefinition struct {
+	Name     string
+	DataType rdbmstool.ColumnDataType
+	Length   int
 }
 
 //GetHashKey is to generate data table equivalent hash key column name
 func (hubDef *HubDefinition) GetHashKey() string {
-	return fmt.Sprintf("%s_hash_key", stringtool.ToSnakeCase(hubDef.Name))
+	return HashKeyColumnName(hubDef.Name)
 }
 
 //GetDbTableName is to generate equivalent data table name
 func (hubDef *HubDefinition) GetDbTableName() string {
-	return fmt.Sprintf("hub_%s_rev%d", stringtool.ToSnakeCase(hubDef.Name), hubDef.Revision)
+	return HubTableName(hubDef.Name, hubDef.Revision)
+}
+
+//GetBusinessKeyColumn is to generate data table column of business key
+func (bk *BusinessKeyDefinition) GetBusinessKeyColumn() (rdbmstool.ColumnDefinition, error) {
+	col := rdbmstool.ColumnDefinition{
+		Name:       stringtool.ToSnakeCase(bk.Name),
+		DataType:   bk.DataType,
+		Length:     bk.Length,
+		IsNullable: false}
+
+	if bk.Name == "" {
+		return col, errors.New("Business key must has a name")
+	}
+
+	switch col.DataType {
+	case 0:
+		col.DataType = rdbmstool.CHAR
+		if col.Length == 0 {
+			col.Length = 100
+		}
+		break
+	case rdbmstool.CHAR, rdbmstool.VARCHAR:
+		if col.Length == 0 {
+			col.Length = 100
+		}
+		break
+	case rdbmstool.INTEGER, rdbmstool.DATE:
+		break
+	default:
+		return col, fmt.Errorf("Unsupported datatype (%s) for business key %s",
+			col.DataType.String(), bk.Name)
+	}
+
+	return col, nil
 }
 
 // GenerateSQL is to generate SQL statement based on hub definition
 func (hubDef *HubDefinition) GenerateSQL() (string, error) {
-	var sql string
+	return hubDef.GenerateDialectSQL(dialect.MYSQL)
+}
 
+// GenerateDialectSQL is to generate SQL statement based on hub definition for given SQL dialect
+func (hubDef *HubDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
 	tableDef := rdbmstool.TableDefinition{
 		Name:        hubDef.GetDbTableName(),
 		PrimaryKey:  []string{hubDef.GetHashKey()},
@@ -35,7 +84,7 @@ func (hubDef *HubDefinition) GenerateSQL() (string, error) {
 		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
 		Indices:     []rdbmstool.IndexKeyDefinition{},
 		Columns: []rdbmstool.ColumnDefinition{
-			createHashKeyColumn(hubDef.Name),
+			createHashKeyColumn(hubDef.Name, hubDef.HashAlgorithm),
 			createLoadDateColumn(),
 			createRecordSourceColumn()}}
 
@@ -43,18 +92,27 @@ func (hubDef *HubDefinition) GenerateSQL() (string, error) {
 		var uks []string
 
 		for _, bk := range hubDef.BusinessKeys {
-			tableDef.Columns = append(tableDef.Columns,
-				rdbmstool.ColumnDefinition{Name: stringtool.ToSnakeCase(bk),
-					DataType: rdbmstool.CHAR, Length: 100, IsNullable: false})
+			col, colErr := bk.GetBusinessKeyColumn()
+			if colErr != nil {
+				return "", colErr
+			}
+
+			for _, existing := range tableDef.Columns {
+				if existing.Name == col.Name {
+					return "", fmt.Errorf("Hub %s has duplicate column %s", hubDef.Name, col.Name)
+				}
+			}
+
+			tableDef.Columns = append(tableDef.Columns, col)
 
-			uks = append(uks, stringtool.ToSnakeCase(bk))
+			uks = append(uks, col.Name)
 		}
 
 		tableDef.UniqueKeys = append(tableDef.UniqueKeys,
 			rdbmstool.UniqueKeyDefinition{ColumnNames: uks})
 	}
 
-	sql, err := rdbmstool.GenerateTableSQL(&tableDef)
+	sql, err := sqlDialect.CreateTableSQL(&tableDef)
 
 	if err != nil {
 		return "", err
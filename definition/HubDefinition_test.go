@@ -3,8 +3,10 @@ package definition
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/guinso/datavault/dialect"
 	"github.com/guinso/rdbmstool"
 
 	//explicitly include GO mysql library
@@ -48,12 +50,12 @@ func TestCreateHub(t *testing.T) {
 	_testCreateHub(tx, t, &HubDefinition{
 		Name:         "Invoice",
 		Revision:     0,
-		BusinessKeys: []string{"InvoiceNo"}})
+		BusinessKeys: []BusinessKeyDefinition{BusinessKeyDefinition{Name: "InvoiceNo"}}})
 
 	_testCreateHub(tx, t, &HubDefinition{
 		Name:         "InvoiceOrder",
 		Revision:     0,
-		BusinessKeys: []string{}})
+		BusinessKeys: []BusinessKeyDefinition{}})
 
 	tx.Rollback()
 }
@@ -81,3 +83,34 @@ func _testCreateHub(db rdbmstool.DbHandlerProxy, t *testing.T, hubDef *HubDefini
 		return
 	}
 }
+
+func TestHubDefinitionTypedBusinessKey(t *testing.T) {
+	hubDef := HubDefinition{
+		Name:     "Account",
+		Revision: 0,
+		BusinessKeys: []BusinessKeyDefinition{
+			BusinessKeyDefinition{Name: "AccountNo", DataType: rdbmstool.INTEGER},
+			BusinessKeyDefinition{Name: "Email", DataType: rdbmstool.VARCHAR, Length: 255},
+			BusinessKeyDefinition{Name: "Branch"}}}
+
+	sql, err := hubDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"\"account_no\" INTEGER NOT NULL",
+		"\"email\" VARCHAR(255) NOT NULL",
+		"\"branch\" CHAR(100) NOT NULL",
+		"UNIQUE (\"account_no\", \"email\", \"branch\")"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+
+	if _, textErr := (&HubDefinition{Name: "Account", BusinessKeys: []BusinessKeyDefinition{
+		BusinessKeyDefinition{Name: "Note", DataType: rdbmstool.TEXT}}}).GenerateDialectSQL(dialect.POSTGRES); textErr == nil {
+		t.Error("Expect error for TEXT business key")
+	}
+}
@@ -1,23 +1,26 @@
 package definition
 
-import (
-	"fmt"
-
-	"github.com/guinso/stringtool"
-)
-
-//HubReference is schema used by Link and Satelink to describe reference to hub
+//HubReference is schema used by Link and Satelink to describe reference to hub;
+//Role is optional, used by link to distinguish multiple references to the same hub,
+//e.g. same-as link (master and duplicate customer) or hierarchical link (employee and manager)
 type HubReference struct {
 	HubName  string
 	Revision int
+	Role     string
 }
 
 // GetDbTableName is to get equivalence database table name
 func (hubRef *HubReference) GetDbTableName() string {
-	return fmt.Sprintf("hub_%s_rev%d", stringtool.ToSnakeCase(hubRef.HubName), hubRef.Revision)
+	return HubTableName(hubRef.HubName, hubRef.Revision)
 }
 
 // GetHashKey is to get equivalence database hash key table column name
 func (hubRef *HubReference) GetHashKey() string {
-	return fmt.Sprintf("%s_hash_key", stringtool.ToSnakeCase(hubRef.HubName))
+	return HashKeyColumnName(hubRef.HubName)
+}
+
+// GetColumnName is to get hash key column name in referring data table;
+// role name is prefixed if it is provided, example: manager_employee_hash_key
+func (hubRef *HubReference) GetColumnName() string {
+	return RoleHashKeyColumnName(hubRef.Role, hubRef.HubName)
 }
@@ -4,8 +4,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
 	"github.com/guinso/rdbmstool"
-	"github.com/guinso/stringtool"
 )
 
 //LinkDefinition is schema to descibe link structure
@@ -13,20 +14,26 @@ type LinkDefinition struct {
 	Name          string
 	Revision      int
 	HubReferences []HubReference
+	HashAlgorithm hashkey.Algorithm
 }
 
 //GetHashKey is to generate data table equivalent hash key column name
 func (linkDef *LinkDefinition) GetHashKey() string {
-	return fmt.Sprintf("%s_hash_key", stringtool.ToSnakeCase(linkDef.Name))
+	return HashKeyColumnName(linkDef.Name)
 }
 
 //GetDbTableName is to generate equivalent data table name
 func (linkDef *LinkDefinition) GetDbTableName() string {
-	return fmt.Sprintf("link_%s_rev%d", stringtool.ToSnakeCase(linkDef.Name), linkDef.Revision)
+	return LinkTableName(linkDef.Name, linkDef.Revision)
 }
 
 // GenerateSQL is to generate SQL statement based on link definition
 func (linkDef *LinkDefinition) GenerateSQL() (string, error) {
+	return linkDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on link definition for given SQL dialect
+func (linkDef *LinkDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
 	if linkDef == nil || linkDef.HubReferences == nil || len(linkDef.HubReferences) < 2 {
 		//why atleast two hub reference?
 		//1. point to main hub
@@ -44,25 +51,34 @@ func (linkDef *LinkDefinition) GenerateSQL() (string, error) {
 		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
 		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
 		Columns: []rdbmstool.ColumnDefinition{
-			createHashKeyColumn(linkDef.Name),
+			createHashKeyColumn(linkDef.Name, linkDef.HashAlgorithm),
 			createLoadDateColumn(),
 			createRecordSourceColumn()}}
 
+	//same hub may be referred more than once (same-as or hierarchical link)
+	//as long as each reference has distinct role
+	columns := map[string]bool{linkDef.GetHashKey(): true}
 	for _, hubRef := range linkDef.HubReferences {
-		tableDef.Columns = append(tableDef.Columns, createHashKeyColumn(hubRef.HubName))
+		if columns[hubRef.GetColumnName()] {
+			return "", fmt.Errorf("link definition has duplicate hub reference column %s, "+
+				"set distinct role for each reference to the same hub", hubRef.GetColumnName())
+		}
+		columns[hubRef.GetColumnName()] = true
+
+		tableDef.Columns = append(tableDef.Columns, createRoleHashKeyColumn(hubRef.Role, hubRef.HubName, linkDef.HashAlgorithm))
 
 		tableDef.Indices = append(tableDef.Indices,
-			rdbmstool.IndexKeyDefinition{ColumnNames: []string{hubRef.GetHashKey()}})
+			rdbmstool.IndexKeyDefinition{ColumnNames: []string{hubRef.GetColumnName()}})
 		tableDef.ForiegnKeys = append(tableDef.ForiegnKeys,
 			rdbmstool.ForeignKeyDefinition{
 				Columns: []rdbmstool.FKColumnDefinition{
 					rdbmstool.FKColumnDefinition{
-						ColumnName:    hubRef.GetHashKey(),
+						ColumnName:    hubRef.GetColumnName(),
 						RefColumnName: hubRef.GetHashKey()}},
 				ReferenceTableName: hubRef.GetDbTableName()})
 	}
 
-	sql, err := rdbmstool.GenerateTableSQL(&tableDef)
+	sql, err := sqlDialect.CreateTableSQL(&tableDef)
 	if err != nil {
 		return "", err
 	}
@@ -3,7 +3,11 @@ package definition
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
 )
 
 func TestCreateLink(t *testing.T) {
@@ -62,3 +66,55 @@ func TestCreateLink(t *testing.T) {
 
 	tx.Rollback()
 }
+
+func TestLinkDefinitionRoleReference(t *testing.T) {
+	linkDef := LinkDefinition{
+		Name: "CustomerSameAs",
+		HubReferences: []HubReference{
+			HubReference{HubName: "Customer", Role: "Master"},
+			HubReference{HubName: "Customer", Role: "Duplicate"}}}
+
+	sql, err := linkDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"\"master_customer_hash_key\" CHAR(32) NOT NULL",
+		"\"duplicate_customer_hash_key\" CHAR(32) NOT NULL",
+		"FOREIGN KEY (\"master_customer_hash_key\") REFERENCES \"hub_customer_rev0\" (\"customer_hash_key\")"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+
+	linkDef.HubReferences[1].Role = "master"
+	if _, dupErr := linkDef.GenerateDialectSQL(dialect.POSTGRES); dupErr == nil {
+		t.Error("Expect error for duplicate hub reference column")
+	}
+}
+
+func TestLinkDefinitionHashAlgorithm(t *testing.T) {
+	linkDef := LinkDefinition{
+		Name:          "CustomerSameAs",
+		HashAlgorithm: hashkey.SHA1,
+		HubReferences: []HubReference{
+			HubReference{HubName: "Customer", Role: "Master"},
+			HubReference{HubName: "Customer", Role: "Duplicate"}}}
+
+	sql, err := linkDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"\"customer_same_as_hash_key\" CHAR(40) NOT NULL",
+		"\"master_customer_hash_key\" CHAR(40) NOT NULL",
+		"\"duplicate_customer_hash_key\" CHAR(40) NOT NULL"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package definition
+
+//LinkReference is schema used by bridge and link satelite to describe reference to link
+type LinkReference struct {
+	LinkName string
+	Revision int
+}
+
+// GetDbTableName is to get equivalence database table name
+func (linkRef *LinkReference) GetDbTableName() string {
+	return LinkTableName(linkRef.LinkName, linkRef.Revision)
+}
+
+// GetHashKey is to get equivalence database hash key table column name
+func (linkRef *LinkReference) GetHashKey() string {
+	return HashKeyColumnName(linkRef.LinkName)
+}
@@ -0,0 +1,95 @@
+package definition
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+)
+
+//NonHistorizedLinkDefinition is schema to describe non-historized (transactional) link
+//structure; unlike link, it carry transaction attributes (e.g. amount of payment) which
+//never change after insert, so it has no satelite
+type NonHistorizedLinkDefinition struct {
+	Name          string
+	Revision      int
+	HubReferences []HubReference
+	Attributes    []SateliteAttributeDefinition
+	HashAlgorithm hashkey.Algorithm
+}
+
+//GetHashKey is to generate data table equivalent hash key column name
+func (nhlDef *NonHistorizedLinkDefinition) GetHashKey() string {
+	return HashKeyColumnName(nhlDef.Name)
+}
+
+//GetDbTableName is to generate equivalent data table name
+func (nhlDef *NonHistorizedLinkDefinition) GetDbTableName() string {
+	return NonHistorizedLinkTableName(nhlDef.Name, nhlDef.Revision)
+}
+
+// GenerateSQL is to generate SQL statement based on non-historized link definition
+func (nhlDef *NonHistorizedLinkDefinition) GenerateSQL() (string, error) {
+	return nhlDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on non-historized link definition
+// for given SQL dialect
+func (nhlDef *NonHistorizedLinkDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if nhlDef == nil || nhlDef.HubReferences == nil || len(nhlDef.HubReferences) < 2 {
+		return "", errors.New("non-historized link definition must has atleast two hub reference")
+	}
+
+	if nhlDef.Attributes == nil || len(nhlDef.Attributes) == 0 {
+		return "", errors.New("non-historized link definition must has atleast one attribute")
+	}
+
+	tableDef := rdbmstool.TableDefinition{
+		Name:        nhlDef.GetDbTableName(),
+		PrimaryKey:  []string{nhlDef.GetHashKey()},
+		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
+		Columns: []rdbmstool.ColumnDefinition{
+			createHashKeyColumn(nhlDef.Name, nhlDef.HashAlgorithm),
+			createLoadDateColumn(),
+			createRecordSourceColumn()}}
+
+	columns := map[string]bool{nhlDef.GetHashKey(): true, LOAD_DATE: true, RECORD_SOURCE: true}
+	for _, hubRef := range nhlDef.HubReferences {
+		if columns[hubRef.GetColumnName()] {
+			return "", fmt.Errorf("non-historized link definition has duplicate hub reference column %s, "+
+				"set distinct role for each reference to the same hub", hubRef.GetColumnName())
+		}
+		columns[hubRef.GetColumnName()] = true
+
+		tableDef.Columns = append(tableDef.Columns, createRoleHashKeyColumn(hubRef.Role, hubRef.HubName, nhlDef.HashAlgorithm))
+
+		tableDef.Indices = append(tableDef.Indices, createIndexKey(hubRef.GetColumnName()))
+		tableDef.ForiegnKeys = append(tableDef.ForiegnKeys,
+			rdbmstool.ForeignKeyDefinition{
+				Columns: []rdbmstool.FKColumnDefinition{
+					rdbmstool.FKColumnDefinition{
+						ColumnName:    hubRef.GetColumnName(),
+						RefColumnName: hubRef.GetHashKey()}},
+				ReferenceTableName: hubRef.GetDbTableName()})
+	}
+
+	for _, attribute := range nhlDef.Attributes {
+		attrCol := createAttributeColumn(attribute)
+		if columns[attrCol.Name] {
+			return "", fmt.Errorf("non-historized link definition has duplicate column %s", attrCol.Name)
+		}
+		columns[attrCol.Name] = true
+
+		tableDef.Columns = append(tableDef.Columns, attrCol)
+	}
+
+	sql, err := sqlDialect.CreateTableSQL(&tableDef)
+	if err != nil {
+		return "", err
+	}
+
+	return sql, nil
+}
@@ -0,0 +1,66 @@
+package definition
+
+import (
+	"errors"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+)
+
+//PitDefinition is schema to describe point in time (PIT) table of a hub; each row
+//keep load date of valid satelite row per hub hash key and snapshot date
+type PitDefinition struct {
+	Name          string
+	Revision      int
+	HubReference  *HubReference
+	Satelites     []SateliteReference
+	HashAlgorithm hashkey.Algorithm
+}
+
+//GetDbTableName is to generate equivalent data table name
+func (pitDef *PitDefinition) GetDbTableName() string {
+	return PitTableName(pitDef.Name, pitDef.Revision)
+}
+
+// GenerateSQL is to generate SQL statement based on PIT definition
+func (pitDef *PitDefinition) GenerateSQL() (string, error) {
+	return pitDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on PIT definition for given SQL dialect
+func (pitDef *PitDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if pitDef == nil {
+		return "", errors.New("Input parameter cannot be null")
+	}
+
+	if pitDef.HubReference == nil {
+		return "", errors.New("PIT has no hub reference")
+	}
+
+	if pitDef.Satelites == nil || len(pitDef.Satelites) == 0 {
+		return "", errors.New("PIT must has atleast one satelite reference")
+	}
+
+	//PIT is derived from hub and satelites, so it has no foreign key and
+	//can be dropped and rebuilt any time
+	tableDef := rdbmstool.TableDefinition{
+		Name: pitDef.GetDbTableName(),
+		Columns: []rdbmstool.ColumnDefinition{
+			createHashKeyColumn(pitDef.HubReference.HubName, pitDef.HashAlgorithm),
+			createSnapshotDateColumn()},
+		PrimaryKey:  []string{pitDef.HubReference.GetHashKey(), SNAPSHOT_DATE},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
+		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
+		Indices: []rdbmstool.IndexKeyDefinition{
+			createIndexKey(SNAPSHOT_DATE)}}
+
+	for _, satRef := range pitDef.Satelites {
+		tableDef.Columns = append(tableDef.Columns, rdbmstool.ColumnDefinition{
+			Name:       satRef.GetLoadDateColumn(),
+			DataType:   rdbmstool.DATETIME,
+			IsNullable: true})
+	}
+
+	return sqlDialect.CreateTableSQL(&tableDef)
+}
@@ -0,0 +1,39 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/dialect"
+)
+
+func TestPitDefinitionGenerateSQL(t *testing.T) {
+	pitDef := PitDefinition{
+		Name:         "Invoice",
+		Revision:     0,
+		HubReference: &HubReference{HubName: "Invoice", Revision: 0},
+		Satelites: []SateliteReference{
+			SateliteReference{SateliteName: "Invoice", Revision: 0},
+			SateliteReference{SateliteName: "InvoiceStatus", Revision: 1}}}
+
+	sql, err := pitDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"CREATE TABLE \"pit_invoice_rev0\"",
+		"\"sat_invoice_rev0_load_date\" TIMESTAMP,",
+		"\"sat_invoice_status_rev1_load_date\" TIMESTAMP",
+		"PRIMARY KEY (\"invoice_hash_key\", \"snapshot_date\")"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+
+	if _, emptyErr := (&PitDefinition{Name: "Invoice",
+		HubReference: &HubReference{HubName: "Invoice"}}).GenerateSQL(); emptyErr == nil {
+		t.Error("Expect error for PIT without satelite reference")
+	}
+}
@@ -0,0 +1,66 @@
+package definition
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+)
+
+//ReferenceDefinition is schema to describe reference table structure; reference table
+//keep code list (e.g. currency or country) which is not a business entity, so it has
+//no hash key; Keys is attribute name(s) used as primary key, e.g. currency code
+type ReferenceDefinition struct {
+	Name       string
+	Revision   int
+	Keys       []string
+	Attributes []SateliteAttributeDefinition
+}
+
+//GetDbTableName is to generate equivalent data table name
+func (refDef *ReferenceDefinition) GetDbTableName() string {
+	return ReferenceTableName(refDef.Name, refDef.Revision)
+}
+
+// GenerateSQL is to generate SQL statement based on reference definition
+func (refDef *ReferenceDefinition) GenerateSQL() (string, error) {
+	return refDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on reference definition for given SQL dialect
+func (refDef *ReferenceDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if refDef == nil {
+		return "", errors.New("Input parameter cannot be null")
+	}
+
+	if refDef.Keys == nil || len(refDef.Keys) == 0 {
+		return "", errors.New("Reference must has atleast one key")
+	}
+
+	keyCols, keyErr := getKeyAttributeColumnNames(refDef.Attributes, refDef.Keys)
+	if keyErr != nil {
+		return "", fmt.Errorf("Reference key %s", keyErr.Error())
+	}
+
+	tableDef := rdbmstool.TableDefinition{
+		Name:        refDef.GetDbTableName(),
+		PrimaryKey:  keyCols,
+		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
+		Indices:     []rdbmstool.IndexKeyDefinition{},
+		Columns: []rdbmstool.ColumnDefinition{
+			createLoadDateColumn(),
+			createRecordSourceColumn()}}
+
+	for _, attribute := range refDef.Attributes {
+		tableDef.Columns = append(tableDef.Columns, createAttributeColumn(attribute))
+	}
+
+	sql, err := sqlDialect.CreateTableSQL(&tableDef)
+	if err != nil {
+		return "", err
+	}
+
+	return sql, nil
+}
@@ -0,0 +1,69 @@
+package definition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+)
+
+func TestReferenceDefinitionGenerateSQL(t *testing.T) {
+	refDef := ReferenceDefinition{
+		Name: "Currency",
+		Keys: []string{"CurrencyCode"},
+		Attributes: []SateliteAttributeDefinition{
+			SateliteAttributeDefinition{Name: "CurrencyCode", DataType: rdbmstool.CHAR, Length: 3},
+			SateliteAttributeDefinition{Name: "Description", DataType: rdbmstool.VARCHAR, Length: 100}}}
+
+	sql, err := refDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"CREATE TABLE \"ref_currency_rev0\"",
+		"\"currency_code\" CHAR(3) NOT NULL",
+		"PRIMARY KEY (\"currency_code\")"} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+
+	refDef.Keys = []string{"Symbol"}
+	if _, keyErr := refDef.GenerateDialectSQL(dialect.POSTGRES); keyErr == nil {
+		t.Error("Expect error for reference key which is not an attribute")
+	}
+}
+
+func TestNonHistorizedLinkDefinitionGenerateSQL(t *testing.T) {
+	nhlDef := NonHistorizedLinkDefinition{
+		Name: "Payment",
+		HubReferences: []HubReference{
+			HubReference{HubName: "Customer"},
+			HubReference{HubName: "Invoice"}},
+		Attributes: []SateliteAttributeDefinition{
+			SateliteAttributeDefinition{Name: "Amount", DataType: rdbmstool.DECIMAL, Length: 10, DecimalPrecision: 2}}}
+
+	sql, err := nhlDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	for _, expected := range []string{
+		"CREATE TABLE \"nhl_payment_rev0\"",
+		"PRIMARY KEY (\"payment_hash_key\")",
+		"\"amount\" NUMERIC(10,2) NOT NULL",
+		"REFERENCES \"hub_invoice_rev0\""} {
+		if !strings.Contains(sql, expected) {
+			t.Errorf("Expect %s in SQL statement: %s", expected, sql)
+		}
+	}
+
+	nhlDef.Attributes = nil
+	if _, attrErr := nhlDef.GenerateDialectSQL(dialect.POSTGRES); attrErr == nil {
+		t.Error("Expect error for non-historized link without attribute")
+	}
+}
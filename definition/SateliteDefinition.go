@@ -4,16 +4,26 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
 	"github.com/guinso/rdbmstool"
 	"github.com/guinso/stringtool"
 )
 
-//SateliteDefinition is schema to describe satelite structure
+//SateliteDefinition is schema to describe satelite structure; satelite refer to either
+//a hub (HubReference) or a link (LinkReference) but not both;
+//HasHashDiff add hash diff column to detect attribute changes;
+//MultiActiveKeys is optional attribute name(s) added into primary key, so a hub (or link)
+//can has multiple active rows at the same time, e.g. phone number type of customer phone
 type SateliteDefinition struct {
-	Name         string
-	HubReference *HubReference
-	Attributes   []SateliteAttributeDefinition
-	Revision     int
+	Name            string
+	HubReference    *HubReference
+	LinkReference   *LinkReference
+	Attributes      []SateliteAttributeDefinition
+	Revision        int
+	HasHashDiff     bool
+	MultiActiveKeys []string
+	HashAlgorithm   hashkey.Algorithm
 }
 
 //SateliteAttributeDefinition is schema to descibe satelite attributes structure
@@ -25,57 +35,125 @@ type SateliteAttributeDefinition struct {
 	DecimalPrecision int
 }
 
+//GetAttributeColumn is to generate data table column of satelite attribute
+func (attribute *SateliteAttributeDefinition) GetAttributeColumn() rdbmstool.ColumnDefinition {
+	return rdbmstool.ColumnDefinition{
+		Name:             stringtool.ToSnakeCase(attribute.Name),
+		DataType:         attribute.DataType,
+		Length:           attribute.Length,
+		IsNullable:       attribute.IsNullable,
+		DecimalPrecision: attribute.DecimalPrecision}
+}
+
 //GetDbTableName is function to generate equivalence datatable name
 func (satDef *SateliteDefinition) GetDbTableName() string {
-	return fmt.Sprintf("sat_%s_rev%d", stringtool.ToSnakeCase(satDef.Name), satDef.Revision)
+	return SateliteTableName(satDef.Name, satDef.Revision)
+}
+
+//IsMultiActive check satelite has multi-active key(s)
+func (satDef *SateliteDefinition) IsMultiActive() bool {
+	return len(satDef.MultiActiveKeys) > 0
+}
+
+//GetParentHashKey is hash key column name of referred hub or link
+func (satDef *SateliteDefinition) GetParentHashKey() string {
+	if satDef.LinkReference != nil {
+		return satDef.LinkReference.GetHashKey()
+	} else if satDef.HubReference != nil {
+		return satDef.HubReference.GetHashKey()
+	}
+
+	return ""
+}
+
+//GetParentDbTableName is data table name of referred hub or link
+func (satDef *SateliteDefinition) GetParentDbTableName() string {
+	if satDef.LinkReference != nil {
+		return satDef.LinkReference.GetDbTableName()
+	} else if satDef.HubReference != nil {
+		return satDef.HubReference.GetDbTableName()
+	}
+
+	return ""
 }
 
 // GenerateSQL is to generate SQL statement based on satelite definition
 func (satDef *SateliteDefinition) GenerateSQL() (string, error) {
+	return satDef.GenerateDialectSQL(dialect.MYSQL)
+}
+
+// GenerateDialectSQL is to generate SQL statement based on satelite definition for given SQL dialect
+func (satDef *SateliteDefinition) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
 	if satDef == nil {
 		return "", errors.New("Input parameter cannot be null")
 	}
 
-	if satDef.HubReference == nil {
-		return "", errors.New("Satelite has no hub reference")
+	if satDef.HubReference == nil && satDef.LinkReference == nil {
+		return "", errors.New("Satelite has no hub or link reference")
+	}
+
+	if satDef.HubReference != nil && satDef.LinkReference != nil {
+		return "", errors.New("Satelite only allow to refer either hub or link")
 	}
 
 	if satDef.Attributes == nil || len(satDef.Attributes) == 0 {
 		return "", errors.New("Satelite must has atleast one attribute")
 	}
 
+	multiActiveCols, multiActiveErr := satDef.getMultiActiveColumnNames()
+	if multiActiveErr != nil {
+		return "", multiActiveErr
+	}
+
 	tableDef := rdbmstool.TableDefinition{
-		Name: fmt.Sprintf("sat_%s_rev%d", stringtool.ToSnakeCase(satDef.Name), satDef.Revision),
+		Name: satDef.GetDbTableName(),
 		Columns: []rdbmstool.ColumnDefinition{
-			createHashKeyColumn(satDef.HubReference.HubName),
+			satDef.createParentHashKeyColumn(),
 			createLoadDateColumn(),
 			createEndDateColumn(),
 			createRecordSourceColumn()},
-		PrimaryKey: []string{satDef.HubReference.GetHashKey(), LOAD_DATE},
+		PrimaryKey: append([]string{satDef.GetParentHashKey(), LOAD_DATE}, multiActiveCols...),
 		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{
 			rdbmstool.ForeignKeyDefinition{
-				ReferenceTableName: satDef.HubReference.GetDbTableName(),
+				ReferenceTableName: satDef.GetParentDbTableName(),
 				Columns: []rdbmstool.FKColumnDefinition{
 					rdbmstool.FKColumnDefinition{
-						ColumnName:    satDef.HubReference.GetHashKey(),
-						RefColumnName: satDef.HubReference.GetHashKey()}}}},
+						ColumnName:    satDef.GetParentHashKey(),
+						RefColumnName: satDef.GetParentHashKey()}}}},
 		UniqueKeys: []rdbmstool.UniqueKeyDefinition{},
 		Indices: []rdbmstool.IndexKeyDefinition{
-			createIndexKey(satDef.HubReference.GetHashKey())}}
+			createIndexKey(satDef.GetParentHashKey())}}
+
+	if satDef.HasHashDiff {
+		tableDef.Columns = append(tableDef.Columns, createHashDiffColumn(satDef.HashAlgorithm))
+	}
 
 	for _, attribute := range satDef.Attributes {
-		tableDef.Columns = append(tableDef.Columns, rdbmstool.ColumnDefinition{
-			Name:             stringtool.ToSnakeCase(attribute.Name),
-			DataType:         attribute.DataType,
-			Length:           attribute.Length,
-			IsNullable:       attribute.IsNullable,
-			DecimalPrecision: attribute.DecimalPrecision})
+		tableDef.Columns = append(tableDef.Columns, createAttributeColumn(attribute))
 	}
 
-	sql, err := rdbmstool.GenerateTableSQL(&tableDef)
+	sql, err := sqlDialect.CreateTableSQL(&tableDef)
 	if err != nil {
 		return "", err
 	}
 
 	return sql, nil
 }
+
+//getMultiActiveColumnNames is to get column name of multi-active keys
+func (satDef *SateliteDefinition) getMultiActiveColumnNames() ([]string, error) {
+	colNames, err := getKeyAttributeColumnNames(satDef.Attributes, satDef.MultiActiveKeys)
+	if err != nil {
+		return nil, fmt.Errorf("Satelite multi-active key %s", err.Error())
+	}
+
+	return colNames, nil
+}
+
+func (satDef *SateliteDefinition) createParentHashKeyColumn() rdbmstool.ColumnDefinition {
+	if satDef.LinkReference != nil {
+		return createHashKeyColumn(satDef.LinkReference.LinkName, satDef.HashAlgorithm)
+	}
+
+	return createHashKeyColumn(satDef.HubReference.HubName, satDef.HashAlgorithm)
+}
@@ -3,8 +3,10 @@ package definition
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/guinso/datavault/dialect"
 	"github.com/guinso/rdbmstool"
 )
 
@@ -114,3 +116,34 @@ func _testCreateSatelite(db rdbmstool.DbHandlerProxy, t *testing.T, satDef *Sate
 		return
 	}
 }
+
+func TestSateliteMultiActiveGenerateSQL(t *testing.T) {
+	satDef := SateliteDefinition{
+		Name:            "CustomerPhone",
+		HubReference:    &HubReference{HubName: "Customer"},
+		MultiActiveKeys: []string{"PhoneType"},
+		Attributes: []SateliteAttributeDefinition{
+			SateliteAttributeDefinition{Name: "PhoneType", DataType: rdbmstool.VARCHAR, Length: 20},
+			SateliteAttributeDefinition{Name: "PhoneNo", DataType: rdbmstool.VARCHAR, Length: 20}}}
+
+	sql, err := satDef.GenerateDialectSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !strings.Contains(sql, "PRIMARY KEY (\"customer_hash_key\", \"load_date\", \"phone_type\")") {
+		t.Errorf("Expect multi-active key in primary key: %s", sql)
+	}
+
+	satDef.MultiActiveKeys = []string{"Extension"}
+	if _, missingErr := satDef.GenerateDialectSQL(dialect.POSTGRES); missingErr == nil {
+		t.Error("Expect error for multi-active key which is not an attribute")
+	}
+
+	satDef.MultiActiveKeys = []string{"PhoneType"}
+	satDef.Attributes[0].IsNullable = true
+	if _, nullableErr := satDef.GenerateDialectSQL(dialect.POSTGRES); nullableErr == nil {
+		t.Error("Expect error for nullable multi-active key")
+	}
+}
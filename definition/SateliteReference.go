@@ -0,0 +1,17 @@
+package definition
+
+//SateliteReference is schema used by PIT to describe reference to satelite
+type SateliteReference struct {
+	SateliteName string
+	Revision     int
+}
+
+// GetDbTableName is to get equivalence database table name
+func (satRef *SateliteReference) GetDbTableName() string {
+	return SateliteTableName(satRef.SateliteName, satRef.Revision)
+}
+
+// GetLoadDateColumn is to get equivalence PIT table column name which keep satelite's load date
+func (satRef *SateliteReference) GetLoadDateColumn() string {
+	return satRef.GetDbTableName() + "_" + LOAD_DATE
+}
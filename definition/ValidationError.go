@@ -0,0 +1,20 @@
+package definition
+
+import (
+	"fmt"
+	"strings"
+)
+
+//ValidationError is list of problem(s) found in data vault definition
+type ValidationError struct {
+	Problems []string
+}
+
+func (validationErr *ValidationError) Error() string {
+	return fmt.Sprintf("%d definition problem(s) found:\n- %s",
+		len(validationErr.Problems), strings.Join(validationErr.Problems, "\n- "))
+}
+
+func (validationErr *ValidationError) add(format string, args ...interface{}) {
+	validationErr.Problems = append(validationErr.Problems, fmt.Sprintf(format, args...))
+}
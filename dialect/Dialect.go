@@ -0,0 +1,92 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/rdbmstool"
+)
+
+//Dialect is database vendor specific SQL syntax used by definition, record and data vault;
+//adding new database vendor is to implement this interface
+type Dialect interface {
+	//Name is database vendor name, example: mysql
+	Name() string
+
+	//DriverName is database/sql driver name
+	DriverName() string
+
+	//DataSourceName is database/sql connection string
+	DataSourceName(address string, username string, password string, dbName string, port int) string
+
+	//QuoteIdentifier is to quote table or column name
+	QuoteIdentifier(name string) string
+
+	//Placeholder is statement argument placeholder at given position; position start from 1
+	Placeholder(index int) string
+
+	//RenderLiteral is to render value as SQL literal of given column datatype
+	RenderLiteral(value interface{}, col rdbmstool.ColumnDefinition) (string, error)
+
+	//ColumnType is vendor's column datatype of given column definition
+	ColumnType(col rdbmstool.ColumnDefinition) (string, error)
+
+	//CreateTableSQL is to generate create table statement(s) of given table definition
+	CreateTableSQL(tableDef *rdbmstool.TableDefinition) (string, error)
+
+	//InsertIgnoreSQL is to convert insert statement into one which skip
+	//row if key column(s) already exists
+	InsertIgnoreSQL(insertSQL string, keyColumns []string) string
+
+	//TypedPlaceholder is question mark placeholder of given column datatype; used where
+	//database unable to infer placeholder datatype, example select list
+	TypedPlaceholder(col rdbmstool.ColumnDefinition) (string, error)
+
+	//SelectValuesSQL is select clause which return placeholder of each column
+	//as single row; used by INSERT ... SELECT statement
+	SelectValuesSQL(cols []rdbmstool.ColumnDefinition) (string, error)
+}
+
+//List of supported database vendor; MYSQL is default vendor
+var (
+	MYSQL    Dialect = MySQLDialect{}
+	POSTGRES Dialect = PostgresDialect{}
+	SQLITE   Dialect = SQLiteDialect{}
+)
+
+//GetDialect is to get supported dialect by vendor name (mysql, postgres or sqlite)
+func GetDialect(name string) (Dialect, error) {
+	for _, dialect := range []Dialect{MYSQL, POSTGRES, SQLITE} {
+		if strings.EqualFold(dialect.Name(), name) {
+			return dialect, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unsupported database vendor: %s", name)
+}
+
+//Rebind is to convert question mark (?) placeholders into dialect's placeholder style
+func Rebind(dialect Dialect, sql string) string {
+	var result strings.Builder
+	index := 0
+	for _, char := range sql {
+		if char == '?' {
+			index++
+			result.WriteString(dialect.Placeholder(index))
+		} else {
+			result.WriteRune(char)
+		}
+	}
+
+	return result.String()
+}
+
+//QuoteIdentifiers is to quote list of table or column names, separated by comma
+func QuoteIdentifiers(dialect Dialect, names []string) string {
+	quoted := make([]string, len(names))
+	for index, name := range names {
+		quoted[index] = dialect.QuoteIdentifier(name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,97 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guinso/rdbmstool"
+)
+
+func TestDialectRebind(t *testing.T) {
+	sql := "SELECT 1 FROM x WHERE a = ? AND b = ?"
+
+	if result := Rebind(POSTGRES, sql); result != "SELECT 1 FROM x WHERE a = $1 AND b = $2" {
+		t.Errorf("Expect numbered placeholders, given %s instead", result)
+	}
+
+	if result := Rebind(MYSQL, sql); result != sql {
+		t.Errorf("Expect question mark placeholders, given %s instead", result)
+	}
+}
+
+func TestDialectRenderLiteral(t *testing.T) {
+	textCol := rdbmstool.ColumnDefinition{Name: "name", DataType: rdbmstool.TEXT}
+
+	mysqlStr, err := MYSQL.RenderLiteral("O'Brien\\", textCol)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if mysqlStr != "'O''Brien\\\\'" {
+		t.Errorf("Expect escaped MySQL string literal, given %s instead", mysqlStr)
+	}
+
+	boolCol := rdbmstool.ColumnDefinition{Name: "flag", DataType: rdbmstool.BOOLEAN}
+	if sqliteBool, _ := SQLITE.RenderLiteral(true, boolCol); sqliteBool != "1" {
+		t.Errorf("Expect SQLite boolean literal 1, given %s instead", sqliteBool)
+	}
+	if pgBool, _ := POSTGRES.RenderLiteral(false, boolCol); pgBool != "FALSE" {
+		t.Errorf("Expect PostgreSQL boolean literal FALSE, given %s instead", pgBool)
+	}
+
+	dateCol := rdbmstool.ColumnDefinition{Name: "issue_date", DataType: rdbmstool.DATE}
+	date := time.Date(2017, 8, 3, 10, 0, 0, 0, time.UTC)
+	if dateStr, _ := POSTGRES.RenderLiteral(date, dateCol); dateStr != "'2017-08-03'" {
+		t.Errorf("Expect date literal, given %s instead", dateStr)
+	}
+
+	decimalCol := rdbmstool.ColumnDefinition{Name: "tax", DataType: rdbmstool.DECIMAL,
+		Length: 10, DecimalPrecision: 2}
+	if decimalStr, _ := MYSQL.RenderLiteral(12.5, decimalCol); decimalStr != "12.50" {
+		t.Errorf("Expect decimal literal 12.50, given %s instead", decimalStr)
+	}
+
+	if _, typeErr := MYSQL.RenderLiteral(12, textCol); typeErr == nil {
+		t.Error("Expect error for mismatch value type")
+	}
+}
+
+func TestDialectInsertIgnoreSQL(t *testing.T) {
+	insertSQL := "INSERT INTO x (a) VALUES (?)"
+
+	if sql := SQLITE.InsertIgnoreSQL(insertSQL, []string{"a"}); !strings.HasSuffix(sql,
+		"ON CONFLICT (\"a\") DO NOTHING") {
+		t.Errorf("Expect ON CONFLICT clause, given %s instead", sql)
+	}
+
+	if sql := MYSQL.InsertIgnoreSQL(insertSQL, []string{"a"}); !strings.HasSuffix(sql,
+		"ON DUPLICATE KEY UPDATE `a` = `a`") {
+		t.Errorf("Expect ON DUPLICATE KEY clause, given %s instead", sql)
+	}
+}
+
+func TestGetDialect(t *testing.T) {
+	for _, name := range []string{"mysql", "Postgres", "sqlite"} {
+		if _, err := GetDialect(name); err != nil {
+			t.Error(err.Error())
+		}
+	}
+
+	if _, err := GetDialect("oracle"); err == nil {
+		t.Error("Expect error for unsupported database vendor")
+	}
+}
+
+func TestPostgresDataSourceName(t *testing.T) {
+	dsn := POSTGRES.DataSourceName("localhost", "postgres", `it's a \secret`, "test", 5432)
+	expected := `host='localhost' port=5432 user='postgres' password='it\'s a \\secret' dbname='test' sslmode='disable'`
+	if dsn != expected {
+		t.Errorf("Expect connection string %s, given %s instead", expected, dsn)
+	}
+
+	dsn = PostgresDialect{SSLMode: "verify-full"}.DataSourceName("db.example.com", "dv", "", "vault", 5432)
+	if !strings.HasSuffix(dsn, "sslmode='verify-full'") {
+		t.Errorf("Expect connection string with sslmode verify-full, given %s instead", dsn)
+	}
+}
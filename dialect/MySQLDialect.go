@@ -0,0 +1,104 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/rdbmstool"
+)
+
+//MySQLDialect is MySQL SQL syntax
+type MySQLDialect struct{}
+
+//Name is database vendor name
+func (MySQLDialect) Name() string {
+	return "mysql"
+}
+
+//DriverName is database/sql driver name
+func (MySQLDialect) DriverName() string {
+	return "mysql"
+}
+
+//DataSourceName is database/sql connection string
+func (MySQLDialect) DataSourceName(address string, username string, password string,
+	dbName string, port int) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8",
+		username, password, address, port, dbName)
+}
+
+//QuoteIdentifier is to quote table or column name with backtick
+func (MySQLDialect) QuoteIdentifier(name string) string {
+	return "`" + name + "`"
+}
+
+//Placeholder is question mark (?)
+func (MySQLDialect) Placeholder(index int) string {
+	return "?"
+}
+
+//RenderLiteral is to render value as SQL literal; backslash is escaped as well
+func (MySQLDialect) RenderLiteral(value interface{}, col rdbmstool.ColumnDefinition) (string, error) {
+	return renderLiteral(value, col, "TRUE", "FALSE",
+		strings.NewReplacer("\\", "\\\\", "'", "''"))
+}
+
+//ColumnType is MySQL column datatype of given column definition
+func (MySQLDialect) ColumnType(col rdbmstool.ColumnDefinition) (string, error) {
+	switch col.DataType {
+	case rdbmstool.CHAR:
+		return fmt.Sprintf("CHAR(%d)", col.Length), nil
+	case rdbmstool.VARCHAR:
+		return fmt.Sprintf("VARCHAR(%d)", col.Length), nil
+	case rdbmstool.TEXT:
+		return "TEXT", nil
+	case rdbmstool.INTEGER:
+		if col.Length > 0 {
+			return fmt.Sprintf("INT(%d)", col.Length), nil
+		}
+		return "INT", nil
+	case rdbmstool.DECIMAL:
+		return fmt.Sprintf("DECIMAL(%d,%d)", col.Length, col.DecimalPrecision), nil
+	case rdbmstool.FLOAT:
+		return "FLOAT", nil
+	case rdbmstool.DATE:
+		return "DATE", nil
+	case rdbmstool.DATETIME:
+		return "DATETIME", nil
+	case rdbmstool.BOOLEAN:
+		return "BOOLEAN", nil
+	default:
+		return "", fmt.Errorf("Unsupported MySQL column datatype: %s", col.DataType.String())
+	}
+}
+
+//CreateTableSQL is to generate create table statement by rdbmstool
+func (MySQLDialect) CreateTableSQL(tableDef *rdbmstool.TableDefinition) (string, error) {
+	return rdbmstool.GenerateTableSQL(tableDef)
+}
+
+//InsertIgnoreSQL is to append ON DUPLICATE KEY UPDATE which keep existing row as it is
+func (dialect MySQLDialect) InsertIgnoreSQL(insertSQL string, keyColumns []string) string {
+	if len(keyColumns) == 0 {
+		return insertSQL
+	}
+
+	keyColumn := dialect.QuoteIdentifier(keyColumns[0])
+
+	return fmt.Sprintf("%s \nON DUPLICATE KEY UPDATE %s = %s", insertSQL, keyColumn, keyColumn)
+}
+
+//TypedPlaceholder is question mark (?) as MySQL accept untyped placeholder
+func (MySQLDialect) TypedPlaceholder(col rdbmstool.ColumnDefinition) (string, error) {
+	return "?", nil
+}
+
+//SelectValuesSQL is select clause from DUAL table
+func (dialect MySQLDialect) SelectValuesSQL(cols []rdbmstool.ColumnDefinition) (string, error) {
+	sql, err := selectValuesSQL(dialect, cols)
+	if err != nil {
+		return "", err
+	}
+
+	return sql + " FROM DUAL", nil
+}
@@ -0,0 +1,112 @@
+package dialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/guinso/rdbmstool"
+)
+
+//PostgresDialect is PostgreSQL SQL syntax; SSLMode is sslmode of connection string
+//(disable, require, verify-ca or verify-full), "disable" is used if not specified
+type PostgresDialect struct {
+	SSLMode string
+}
+
+//Name is database vendor name
+func (PostgresDialect) Name() string {
+	return "postgres"
+}
+
+//DriverName is database/sql driver name
+func (PostgresDialect) DriverName() string {
+	return "postgres"
+}
+
+//DataSourceName is database/sql connection string; each value is quoted, so value
+//which contains space or quote (e.g. password) is passed as it is
+func (postgres PostgresDialect) DataSourceName(address string, username string, password string,
+	dbName string, port int) string {
+	sslMode := postgres.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		quoteDSNValue(address), port, quoteDSNValue(username), quoteDSNValue(password),
+		quoteDSNValue(dbName), quoteDSNValue(sslMode))
+}
+
+//quoteDSNValue is to quote connection string value with single quote;
+//backslash and single quote within value are escaped by backslash
+func quoteDSNValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+//QuoteIdentifier is to quote table or column name with double quote
+func (PostgresDialect) QuoteIdentifier(name string) string {
+	return "\"" + name + "\""
+}
+
+//Placeholder is numbered placeholder, example: $1
+func (PostgresDialect) Placeholder(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+//RenderLiteral is to render value as SQL literal
+func (PostgresDialect) RenderLiteral(value interface{}, col rdbmstool.ColumnDefinition) (string, error) {
+	return renderLiteral(value, col, "TRUE", "FALSE", strings.NewReplacer("'", "''"))
+}
+
+//ColumnType is PostgreSQL column datatype of given column definition
+func (PostgresDialect) ColumnType(col rdbmstool.ColumnDefinition) (string, error) {
+	switch col.DataType {
+	case rdbmstool.CHAR:
+		return fmt.Sprintf("CHAR(%d)", col.Length), nil
+	case rdbmstool.VARCHAR:
+		return fmt.Sprintf("VARCHAR(%d)", col.Length), nil
+	case rdbmstool.TEXT:
+		return "TEXT", nil
+	case rdbmstool.INTEGER:
+		return "INTEGER", nil
+	case rdbmstool.DECIMAL:
+		return fmt.Sprintf("NUMERIC(%d,%d)", col.Length, col.DecimalPrecision), nil
+	case rdbmstool.FLOAT:
+		return "REAL", nil
+	case rdbmstool.DATE:
+		return "DATE", nil
+	case rdbmstool.DATETIME:
+		return "TIMESTAMP", nil
+	case rdbmstool.BOOLEAN:
+		return "BOOLEAN", nil
+	default:
+		return "", fmt.Errorf("Unsupported PostgreSQL column datatype: %s", col.DataType.String())
+	}
+}
+
+//CreateTableSQL is to generate ANSI style create table statement
+func (dialect PostgresDialect) CreateTableSQL(tableDef *rdbmstool.TableDefinition) (string, error) {
+	return createStandardTableSQL(dialect, tableDef)
+}
+
+//InsertIgnoreSQL is to append ON CONFLICT DO NOTHING
+func (dialect PostgresDialect) InsertIgnoreSQL(insertSQL string, keyColumns []string) string {
+	return insertOnConflictSQL(dialect, insertSQL, keyColumns)
+}
+
+//TypedPlaceholder is question mark placeholder casted into column datatype
+//as PostgreSQL unable to infer placeholder datatype in select list
+func (dialect PostgresDialect) TypedPlaceholder(col rdbmstool.ColumnDefinition) (string, error) {
+	colType, err := dialect.ColumnType(col)
+	if err != nil {
+		return "", err
+	}
+
+	return "CAST(? AS " + colType + ")", nil
+}
+
+//SelectValuesSQL is select clause without table
+func (dialect PostgresDialect) SelectValuesSQL(cols []rdbmstool.ColumnDefinition) (string, error) {
+	return selectValuesSQL(dialect, cols)
+}
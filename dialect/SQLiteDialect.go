@@ -0,0 +1,91 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/rdbmstool"
+)
+
+//SQLiteDialect is SQLite SQL syntax
+type SQLiteDialect struct{}
+
+//Name is database vendor name
+func (SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+//DriverName is database/sql driver name
+func (SQLiteDialect) DriverName() string {
+	return "sqlite3"
+}
+
+//DataSourceName is database/sql connection string; only dbName (file path) is used,
+//use ":memory:" to create in-memory database
+func (SQLiteDialect) DataSourceName(address string, username string, password string,
+	dbName string, port int) string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on", dbName)
+}
+
+//QuoteIdentifier is to quote table or column name with double quote
+func (SQLiteDialect) QuoteIdentifier(name string) string {
+	return "\"" + name + "\""
+}
+
+//Placeholder is question mark (?)
+func (SQLiteDialect) Placeholder(index int) string {
+	return "?"
+}
+
+//RenderLiteral is to render value as SQL literal; SQLite keep boolean as 1 or 0
+func (SQLiteDialect) RenderLiteral(value interface{}, col rdbmstool.ColumnDefinition) (string, error) {
+	return renderLiteral(value, col, "1", "0", strings.NewReplacer("'", "''"))
+}
+
+//ColumnType is SQLite declared column datatype of given column definition;
+//declared datatype is kept as it is, so it can be read back by meta reader
+func (SQLiteDialect) ColumnType(col rdbmstool.ColumnDefinition) (string, error) {
+	switch col.DataType {
+	case rdbmstool.CHAR:
+		return fmt.Sprintf("CHAR(%d)", col.Length), nil
+	case rdbmstool.VARCHAR:
+		return fmt.Sprintf("VARCHAR(%d)", col.Length), nil
+	case rdbmstool.TEXT:
+		return "TEXT", nil
+	case rdbmstool.INTEGER:
+		return "INTEGER", nil
+	case rdbmstool.DECIMAL:
+		return fmt.Sprintf("DECIMAL(%d,%d)", col.Length, col.DecimalPrecision), nil
+	case rdbmstool.FLOAT:
+		return "FLOAT", nil
+	case rdbmstool.DATE:
+		return "DATE", nil
+	case rdbmstool.DATETIME:
+		return "DATETIME", nil
+	case rdbmstool.BOOLEAN:
+		return "BOOLEAN", nil
+	default:
+		return "", fmt.Errorf("Unsupported SQLite column datatype: %s", col.DataType.String())
+	}
+}
+
+//CreateTableSQL is to generate ANSI style create table statement
+func (dialect SQLiteDialect) CreateTableSQL(tableDef *rdbmstool.TableDefinition) (string, error) {
+	return createStandardTableSQL(dialect, tableDef)
+}
+
+//InsertIgnoreSQL is to append ON CONFLICT DO NOTHING
+func (dialect SQLiteDialect) InsertIgnoreSQL(insertSQL string, keyColumns []string) string {
+	return insertOnConflictSQL(dialect, insertSQL, keyColumns)
+}
+
+//TypedPlaceholder is question mark (?); SQLite column has no strict datatype,
+//casting placeholder may convert text value such as date time into number
+func (SQLiteDialect) TypedPlaceholder(col rdbmstool.ColumnDefinition) (string, error) {
+	return "?", nil
+}
+
+//SelectValuesSQL is select clause without table
+func (dialect SQLiteDialect) SelectValuesSQL(cols []rdbmstool.ColumnDefinition) (string, error) {
+	return selectValuesSQL(dialect, cols)
+}
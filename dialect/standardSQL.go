@@ -0,0 +1,136 @@
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guinso/rdbmstool"
+)
+
+//createStandardTableSQL is to generate ANSI style create table statement for
+//given dialect, followed by create index statement(s)
+func createStandardTableSQL(dialect Dialect, tableDef *rdbmstool.TableDefinition) (string, error) {
+	if tableDef == nil || len(tableDef.Columns) == 0 {
+		return "", fmt.Errorf("table definition must has atleast one column")
+	}
+
+	quote := dialect.QuoteIdentifier
+
+	var lines []string
+	for _, col := range tableDef.Columns {
+		colType, typeErr := dialect.ColumnType(col)
+		if typeErr != nil {
+			return "", fmt.Errorf("Column %s: %s", col.Name, typeErr.Error())
+		}
+
+		line := quote(col.Name) + " " + colType
+		if !col.IsNullable {
+			line = line + " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+
+	if len(tableDef.PrimaryKey) > 0 {
+		lines = append(lines, fmt.Sprintf("PRIMARY KEY (%s)",
+			QuoteIdentifiers(dialect, tableDef.PrimaryKey)))
+	}
+
+	for _, uk := range tableDef.UniqueKeys {
+		lines = append(lines, fmt.Sprintf("UNIQUE (%s)", QuoteIdentifiers(dialect, uk.ColumnNames)))
+	}
+
+	for _, fk := range tableDef.ForiegnKeys {
+		var cols []string
+		var refCols []string
+		for _, fkCol := range fk.Columns {
+			cols = append(cols, fkCol.ColumnName)
+			refCols = append(refCols, fkCol.RefColumnName)
+		}
+
+		lines = append(lines, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+			QuoteIdentifiers(dialect, cols), quote(fk.ReferenceTableName),
+			QuoteIdentifiers(dialect, refCols)))
+	}
+
+	sql := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", quote(tableDef.Name), strings.Join(lines, ",\n  "))
+
+	for _, index := range tableDef.Indices {
+		sql = sql + fmt.Sprintf(";\nCREATE INDEX %s ON %s (%s)",
+			quote(tableDef.Name+"_"+strings.Join(index.ColumnNames, "_")+"_idx"),
+			quote(tableDef.Name), QuoteIdentifiers(dialect, index.ColumnNames))
+	}
+
+	return sql, nil
+}
+
+//insertOnConflictSQL is to append ANSI style ON CONFLICT DO NOTHING into insert statement
+func insertOnConflictSQL(dialect Dialect, insertSQL string, keyColumns []string) string {
+	if len(keyColumns) == 0 {
+		return insertSQL + " \nON CONFLICT DO NOTHING"
+	}
+
+	return fmt.Sprintf("%s \nON CONFLICT (%s) DO NOTHING",
+		insertSQL, QuoteIdentifiers(dialect, keyColumns))
+}
+
+//selectValuesSQL is select clause of dialect's typed placeholders
+func selectValuesSQL(dialect Dialect, cols []rdbmstool.ColumnDefinition) (string, error) {
+	values := make([]string, len(cols))
+	for index, col := range cols {
+		placeholder, err := dialect.TypedPlaceholder(col)
+		if err != nil {
+			return "", err
+		}
+
+		values[index] = placeholder
+	}
+
+	return "SELECT " + strings.Join(values, ", "), nil
+}
+
+//renderLiteral is to render value as SQL literal of given column datatype;
+//string literal is escaped by escaper
+func renderLiteral(value interface{}, col rdbmstool.ColumnDefinition,
+	trueLiteral string, falseLiteral string, escaper *strings.Replacer) (string, error) {
+	if value == nil {
+		return "NULL", nil
+	}
+
+	metaType := reflect.TypeOf(value)
+	dataType := col.DataType
+
+	if dataType == rdbmstool.BOOLEAN && metaType.Kind() == reflect.Bool {
+		if value.(bool) {
+			return trueLiteral, nil
+		}
+		return falseLiteral, nil
+
+	} else if dataType == rdbmstool.DATE && metaType == reflect.TypeOf(time.Time{}) {
+		return "'" + value.(time.Time).Format("2006-01-02") + "'", nil
+
+	} else if dataType == rdbmstool.DATETIME && metaType == reflect.TypeOf(time.Time{}) {
+		return "'" + value.(time.Time).Format("2006-01-02 15:04:05") + "'", nil
+
+	} else if (dataType == rdbmstool.DECIMAL || dataType == rdbmstool.FLOAT) &&
+		(metaType.Kind() == reflect.Float32 || metaType.Kind() == reflect.Float64) {
+		tmpFloat := reflect.ValueOf(value).Float()
+		if dataType == rdbmstool.DECIMAL {
+			return strconv.FormatFloat(tmpFloat, 'f', col.DecimalPrecision, 64), nil
+		}
+		return strconv.FormatFloat(tmpFloat, 'f', -1, 64), nil
+
+	} else if dataType == rdbmstool.INTEGER && metaType.Kind() == reflect.Int {
+		return strconv.Itoa(value.(int)), nil
+
+	} else if (dataType == rdbmstool.TEXT || dataType == rdbmstool.CHAR ||
+		dataType == rdbmstool.VARCHAR) && metaType.Kind() == reflect.String {
+		return "'" + escaper.Replace(value.(string)) + "'", nil
+
+	} else {
+		return "", errors.New("value type not match: value type is: " + metaType.Name())
+	}
+}
@@ -13,10 +13,18 @@ type DataVaultMetaReader interface {
 		dbHandler rdbmstool.DbHandlerProxy) (*definition.LinkDefinition, error)
 	GetSateliteDefinition(satName string, revision int,
 		dbHandler rdbmstool.DbHandlerProxy) (*definition.SateliteDefinition, error)
+	GetEffectivitySateliteDefinition(satName string, revision int,
+		dbHandler rdbmstool.DbHandlerProxy) (*definition.EffectivitySateliteDefinition, error)
+	GetReferenceDefinition(refName string, revision int,
+		dbHandler rdbmstool.DbHandlerProxy) (*definition.ReferenceDefinition, error)
+	GetNonHistorizedLinkDefinition(linkName string, revision int,
+		dbHandler rdbmstool.DbHandlerProxy) (*definition.NonHistorizedLinkDefinition, error)
 
-	GetAllHubs(dbHandler rdbmstool.DbHandlerProxy) []EntityInfo
-	GetAllLinks(dbHandler rdbmstool.DbHandlerProxy) []EntityInfo
-	GetAllSatelites(dbHandler rdbmstool.DbHandlerProxy) []EntityInfo
+	GetAllHubs(dbHandler rdbmstool.DbHandlerProxy) ([]EntityInfo, error)
+	GetAllLinks(dbHandler rdbmstool.DbHandlerProxy) ([]EntityInfo, error)
+	GetAllSatelites(dbHandler rdbmstool.DbHandlerProxy) ([]EntityInfo, error)
+
+	GetDataVaultDefinition(dbHandler rdbmstool.DbHandlerProxy) (*definition.DataVaultDefinition, error)
 
 	SearchEntities(dbHandler rdbmstool.DbHandlerProxy, searchKeyword string) []EntityInfo
 
@@ -33,14 +41,17 @@ type EntityInfo struct {
 }
 
 type HubRelationship struct {
-	HubName     string
-	HubRevision int
-	Satelites   []definition.SateliteDefinition
-	Links       []HubLinkRelationship
+	HubName            string
+	HubRevision        int
+	Satelites          []definition.SateliteDefinition
+	Links              []HubLinkRelationship
+	NonHistorizedLinks []definition.NonHistorizedLinkDefinition
 }
 
 type HubLinkRelationship struct {
-	Definition *definition.LinkDefinition
-	Hubs       []definition.HubDefinition
-	Satelites  []definition.SateliteDefinition
+	Definition           *definition.LinkDefinition
+	Hubs                 []definition.HubDefinition
+	Satelites            []definition.SateliteDefinition
+	LinkSatelites        []definition.SateliteDefinition
+	EffectivitySatelites []definition.EffectivitySateliteDefinition
 }
@@ -0,0 +1,126 @@
+package dvmeta
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/rdbmstool"
+)
+
+//ReadError is list of data table(s) which fail to be read into data vault definition,
+//or fail to be listed as its name is not valid data vault table name
+type ReadError struct {
+	Problems []string
+}
+
+func (readErr *ReadError) Error() string {
+	return fmt.Sprintf("%d data table(s) fail to be read:\n- %s",
+		len(readErr.Problems), strings.Join(readErr.Problems, "\n- "))
+}
+
+func (readErr *ReadError) add(entityType definition.EntityType, info EntityInfo, err error) {
+	readErr.Problems = append(readErr.Problems, fmt.Sprintf("%s %s revision %d: %s",
+		entityType, info.Name, info.Revision, err.Error()))
+}
+
+//merge collect problems of listing error into read error; other error is returned as it is
+func (readErr *ReadError) merge(err error) error {
+	if listErr, ok := err.(*ReadError); ok {
+		readErr.Problems = append(readErr.Problems, listErr.Problems...)
+		return nil
+	}
+
+	return err
+}
+
+//ParseEntityInfos convert data table names into entity infos; table name which is not
+//valid data vault table name is reported in *ReadError together with remaining entity infos
+func ParseEntityInfos(tableNames []string) ([]EntityInfo, error) {
+	var result []EntityInfo
+	readErr := ReadError{}
+	for _, tableName := range tableNames {
+		entity, name, revision, err := ExtractDbEntityName(tableName)
+		if err != nil {
+			readErr.Problems = append(readErr.Problems,
+				fmt.Sprintf("data table %s: %s", tableName, err.Error()))
+			continue
+		}
+
+		result = append(result, EntityInfo{
+			Type:     entity,
+			Name:     name,
+			Revision: revision})
+	}
+
+	if len(readErr.Problems) > 0 {
+		return result, &readErr
+	}
+
+	return result, nil
+}
+
+//ReadDataVault read all hubs, links and satelites (all revisions) into data vault definition;
+//malformed data table does not stop reading, instead definition of remaining entities
+//is returned together with *ReadError which list every malformed data table, including
+//data table which can not be listed as its name is not valid data vault table name
+func ReadDataVault(metaReader DataVaultMetaReader, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.DataVaultDefinition, error) {
+	readErr := ReadError{}
+
+	hubInfos, hubErr := metaReader.GetAllHubs(dbHandler)
+	if mergeErr := readErr.merge(hubErr); mergeErr != nil {
+		return nil, mergeErr
+	}
+
+	linkInfos, linkErr := metaReader.GetAllLinks(dbHandler)
+	if mergeErr := readErr.merge(linkErr); mergeErr != nil {
+		return nil, mergeErr
+	}
+
+	satInfos, satErr := metaReader.GetAllSatelites(dbHandler)
+	if mergeErr := readErr.merge(satErr); mergeErr != nil {
+		return nil, mergeErr
+	}
+
+	dvDef := definition.DataVaultDefinition{
+		Hubs:      []definition.HubDefinition{},
+		Satelites: []definition.SateliteDefinition{},
+		Links:     []definition.LinkDefinition{}}
+
+	for _, info := range hubInfos {
+		hubDef, err := metaReader.GetHubDefinition(info.Name, info.Revision, dbHandler)
+		if err != nil {
+			readErr.add(definition.HUB, info, err)
+			continue
+		}
+
+		dvDef.Hubs = append(dvDef.Hubs, *hubDef)
+	}
+
+	for _, info := range linkInfos {
+		linkDef, err := metaReader.GetLinkDefinition(info.Name, info.Revision, dbHandler)
+		if err != nil {
+			readErr.add(definition.LINK, info, err)
+			continue
+		}
+
+		dvDef.Links = append(dvDef.Links, *linkDef)
+	}
+
+	for _, info := range satInfos {
+		satDef, err := metaReader.GetSateliteDefinition(info.Name, info.Revision, dbHandler)
+		if err != nil {
+			readErr.add(definition.SATELITE, info, err)
+			continue
+		}
+
+		dvDef.Satelites = append(dvDef.Satelites, *satDef)
+	}
+
+	if len(readErr.Problems) > 0 {
+		return &dvDef, &readErr
+	}
+
+	return &dvDef, nil
+}
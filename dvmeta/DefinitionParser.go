@@ -0,0 +1,614 @@
+package dvmeta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//ParseHubDefinition convert hub data table definition into hub definition
+func ParseHubDefinition(hubName string, revision int, tableDef *rdbmstool.TableDefinition) (
+	*definition.HubDefinition, error) {
+
+	hubDbName := definition.HubTableName(hubName, revision)
+
+	hubDef := definition.HubDefinition{
+		Name:     hubName,
+		Revision: revision}
+	hubHashKey := makeDVHashKey(hubName)
+	hasHashKeyCol := false
+	hasLoadDateCol := false
+	hasRecordSourceCol := false
+	rowCount := 0
+	for _, col := range tableDef.Columns {
+		rowCount++
+
+		if strings.Compare(col.Name, hubHashKey) == 0 && col.DataType == rdbmstool.CHAR {
+			algorithm, algoErr := parseHashAlgorithm(hubDbName, col)
+			if algoErr != nil {
+				return nil, algoErr
+			}
+
+			hubDef.HashAlgorithm = algorithm
+			hasHashKeyCol = true
+		} else if strings.Compare(col.Name, "record_source") == 0 && col.DataType == rdbmstool.CHAR {
+			hasRecordSourceCol = true
+		} else if strings.Compare(col.Name, "load_date") == 0 && col.DataType == rdbmstool.DATETIME {
+			hasLoadDateCol = true
+		} else {
+			//append business key
+			bk, bkErr := parseBusinessKeyColumn(col)
+			if bkErr != nil {
+				return nil, bkErr
+			}
+
+			hubDef.BusinessKeys = append(hubDef.BusinessKeys, *bk)
+		}
+	}
+
+	if rowCount == 0 {
+		return nil, fmt.Errorf("Data table %s not found in database", hubDbName)
+	}
+
+	if !hasHashKeyCol {
+		return nil, fmt.Errorf("Hash key column not found in hub %s", hubDbName)
+	}
+
+	if !hasLoadDateCol {
+		return nil, fmt.Errorf("Load date column not found in hub %s", hubDbName)
+	}
+
+	if !hasRecordSourceCol {
+		return nil, fmt.Errorf("Record source column not found in hub %s", hubDbName)
+	}
+
+	return &hubDef, nil
+}
+
+//ParseLinkDefinition convert link data table definition into link definition
+func ParseLinkDefinition(linkName string, revision int, tableDef *rdbmstool.TableDefinition) (
+	*definition.LinkDefinition, error) {
+
+	linkDbName := definition.LinkTableName(linkName, revision)
+
+	linkDefinition := definition.LinkDefinition{
+		Name:          linkName,
+		Revision:      revision,
+		HubReferences: []definition.HubReference{}}
+
+	hasHashKey := false
+	hasLoadDate := false
+	hasRecordSource := false
+
+	expectedhasKey := makeDVHashKey(linkName)
+	for _, col := range tableDef.Columns {
+		switch col.DataType {
+		case rdbmstool.CHAR:
+			if strings.Compare(col.Name, expectedhasKey) == 0 {
+				algorithm, algoErr := parseHashAlgorithm(linkDbName, col)
+				if algoErr != nil {
+					return nil, algoErr
+				}
+
+				linkDefinition.HashAlgorithm = algorithm
+				hasHashKey = true
+			} else if strings.Compare(col.Name, "record_source") == 0 {
+				hasRecordSource = true
+			}
+			break
+		case rdbmstool.DATETIME:
+			if strings.Compare(col.Name, "load_date") == 0 {
+				hasLoadDate = true
+			}
+			break
+		default:
+			return nil, fmt.Errorf(
+				"Unsupported datatype (%s) parse into LinkDefinition", col.DataType.String())
+		}
+	}
+
+	if !hasHashKey {
+		return nil, fmt.Errorf("Hash key column not found in link %s", linkDbName)
+	}
+
+	if !hasLoadDate {
+		return nil, fmt.Errorf("Load date column not found in link %s", linkDbName)
+	}
+
+	if !hasRecordSource {
+		return nil, fmt.Errorf("Record source column not found in link %s", linkDbName)
+	}
+
+	for _, fk := range tableDef.ForiegnKeys {
+		if len(fk.Columns) != 1 {
+			return nil, fmt.Errorf("Link entity only support one pair of FK reference"+
+				" but found %s has %d pair instead", fk.Name, len(fk.Columns))
+		}
+
+		entityType, name, revision, extractErr := ExtractDbEntityName(fk.ReferenceTableName)
+		if extractErr != nil {
+			return nil, extractErr
+		}
+
+		if entityType == definition.HUB {
+			hubRef, refErr := parseHubReference(linkDbName, name, revision, fk.Columns[0].ColumnName)
+			if refErr != nil {
+				return nil, refErr
+			}
+
+			linkDefinition.HubReferences = append(linkDefinition.HubReferences, *hubRef)
+		}
+	}
+
+	if len(linkDefinition.HubReferences) < 2 {
+		return nil, fmt.Errorf("invalid link entity: atleast two hub references must be presense but found %d reference only",
+			len(linkDefinition.HubReferences))
+	}
+
+	return &linkDefinition, nil
+}
+
+//ParseSateliteDefinition convert satelite data table definition into satelite definition
+func ParseSateliteDefinition(satName string, revision int, tableDef *rdbmstool.TableDefinition) (
+	*definition.SateliteDefinition, error) {
+
+	satDbName := definition.SateliteTableName(satName, revision)
+
+	satDefinition := definition.SateliteDefinition{
+		Name:       satName,
+		Revision:   revision,
+		Attributes: []definition.SateliteAttributeDefinition{},
+	}
+
+	hasHashKey := false
+	hasLoadDate := false
+	hasEndDate := false
+	hasRecordSource := false
+	var hashDiffCol rdbmstool.ColumnDefinition
+	//validate one and only foreign key
+	if len(tableDef.ForiegnKeys) != 1 {
+		return nil, fmt.Errorf("Satelite %s only allow one FK,"+
+			" but found %d instead", satName, len(tableDef.ForiegnKeys))
+	}
+	fk := tableDef.ForiegnKeys[0]
+	if len(fk.Columns) != 1 {
+		return nil, fmt.Errorf("Satelite %s FK only allow one pair "+
+			"binding but found %d instead", satName, len(fk.Columns))
+	}
+	entity, refName, refrev, refErr := ExtractDbEntityName(fk.ReferenceTableName)
+	if refErr != nil {
+		return nil, fmt.Errorf("Satelite %s FK has invalid reference table, %s: %s",
+			satName, fk.ReferenceTableName, refErr.Error())
+	}
+	if entity == definition.HUB {
+		satDefinition.HubReference = &definition.HubReference{
+			HubName:  refName,
+			Revision: refrev}
+	} else if entity == definition.LINK {
+		satDefinition.LinkReference = &definition.LinkReference{
+			LinkName: refName,
+			Revision: refrev}
+	} else {
+		return nil, fmt.Errorf("Satelite %s FK only allow to refer hub or link entity but found %s",
+			satName, entity.String())
+	}
+
+	colHashKey := definition.HashKeyColumnName(refName)
+	for _, col := range tableDef.Columns {
+		if col.DataType == rdbmstool.CHAR && strings.Compare(col.Name, "record_source") == 0 {
+			hasRecordSource = true
+		} else if col.DataType == rdbmstool.CHAR && strings.Compare(col.Name, colHashKey) == 0 {
+			algorithm, algoErr := parseHashAlgorithm(satDbName, col)
+			if algoErr != nil {
+				return nil, algoErr
+			}
+
+			satDefinition.HashAlgorithm = algorithm
+			hasHashKey = true
+		} else if col.DataType == rdbmstool.CHAR && strings.Compare(col.Name, definition.HASH_DIFF) == 0 {
+			hashDiffCol = col
+			satDefinition.HasHashDiff = true
+		} else if col.DataType == rdbmstool.DATETIME && strings.Compare(col.Name, "load_date") == 0 {
+			hasLoadDate = true
+		} else if col.DataType == rdbmstool.DATETIME && strings.Compare(col.Name, "end_date") == 0 {
+			hasEndDate = true
+		} else {
+			attr, attrErr := parseAttributeColumn(col)
+			if attrErr != nil {
+				return nil, fmt.Errorf("Unsupported datatype for Satelite definition: %s",
+					col.DataType.String())
+			}
+
+			satDefinition.Attributes = append(satDefinition.Attributes, *attr)
+		}
+	}
+
+	if !hasHashKey {
+		return nil, fmt.Errorf("Hash key column not found in satelite %s", satDbName)
+	}
+
+	if satDefinition.HasHashDiff && hashDiffCol.Length != satDefinition.HashAlgorithm.Length() {
+		return nil, fmt.Errorf("Hash diff column of satelite %s has length %d, "+
+			"hash key column has length %d", satDbName, hashDiffCol.Length, satDefinition.HashAlgorithm.Length())
+	}
+
+	if !hasLoadDate {
+		return nil, fmt.Errorf("Load date column not found in satelite %s", satDbName)
+	}
+
+	if !hasEndDate {
+		return nil, fmt.Errorf("End date column not found in satelite %s", satDbName)
+	}
+
+	if !hasRecordSource {
+		return nil, fmt.Errorf("Record source column not found in satelite %s", satDbName)
+	}
+
+	//primary key column other than hash key and load date is multi-active key
+	for _, pk := range tableDef.PrimaryKey {
+		if strings.Compare(pk, definition.HashKeyColumnName(refName)) != 0 &&
+			strings.Compare(pk, definition.LOAD_DATE) != 0 {
+			satDefinition.MultiActiveKeys = append(satDefinition.MultiActiveKeys,
+				stringtool.SnakeToCamelCase(pk))
+		}
+	}
+
+	return &satDefinition, nil
+}
+
+//ParseEffectivitySateliteDefinition convert effectivity satelite data table definition
+//into effectivity satelite definition
+func ParseEffectivitySateliteDefinition(satName string, revision int, tableDef *rdbmstool.TableDefinition) (
+	*definition.EffectivitySateliteDefinition, error) {
+
+	satDbName := definition.EffectivitySateliteTableName(satName, revision)
+
+	esatDefinition := definition.EffectivitySateliteDefinition{
+		Name:     satName,
+		Revision: revision}
+
+	//validate FK refer to one link and its driving key hub
+	if len(tableDef.ForiegnKeys) != 2 {
+		return nil, fmt.Errorf("Effectivity satelite %s only allow two FK,"+
+			" but found %d instead", satName, len(tableDef.ForiegnKeys))
+	}
+	for _, fk := range tableDef.ForiegnKeys {
+		if len(fk.Columns) != 1 {
+			return nil, fmt.Errorf("Effectivity satelite %s FK only allow one pair "+
+				"binding but found %d instead", satName, len(fk.Columns))
+		}
+
+		entity, refName, refRev, refErr := ExtractDbEntityName(fk.ReferenceTableName)
+		if refErr != nil {
+			return nil, fmt.Errorf("Effectivity satelite %s FK has invalid reference table, %s: %s",
+				satName, fk.ReferenceTableName, refErr.Error())
+		}
+
+		if entity == definition.LINK && esatDefinition.LinkReference == nil {
+			esatDefinition.LinkReference = &definition.LinkReference{
+				LinkName: refName,
+				Revision: refRev}
+		} else if entity == definition.HUB && esatDefinition.DrivingKey == nil {
+			hubRef, hubRefErr := parseHubReference(satDbName, refName, refRev, fk.Columns[0].ColumnName)
+			if hubRefErr != nil {
+				return nil, hubRefErr
+			}
+
+			esatDefinition.DrivingKey = hubRef
+		} else {
+			return nil, fmt.Errorf("Effectivity satelite %s FK only allow to refer one link "+
+				"and one hub but found %s", satName, fk.ReferenceTableName)
+		}
+	}
+	if esatDefinition.LinkReference == nil || esatDefinition.DrivingKey == nil {
+		return nil, fmt.Errorf("Effectivity satelite %s must refer to one link and one hub", satName)
+	}
+
+	expectedCols := map[string]bool{
+		esatDefinition.LinkReference.GetHashKey(): false,
+		esatDefinition.DrivingKey.GetColumnName(): false,
+		definition.LOAD_DATE:                      false,
+		definition.START_DATE:                     false,
+		definition.END_DATE:                       false,
+		definition.RECORD_SOURCE:                  false}
+	for _, col := range tableDef.Columns {
+		if _, ok := expectedCols[col.Name]; !ok {
+			return nil, fmt.Errorf("Unrecognized column found in effectivity satelite %s: %s",
+				satDbName, col.Name)
+		}
+
+		expectedCols[col.Name] = true
+	}
+
+	for _, colName := range []string{
+		esatDefinition.LinkReference.GetHashKey(),
+		esatDefinition.DrivingKey.GetColumnName(),
+		definition.LOAD_DATE,
+		definition.START_DATE,
+		definition.END_DATE,
+		definition.RECORD_SOURCE} {
+		if !expectedCols[colName] {
+			return nil, fmt.Errorf("Column %s not found in effectivity satelite %s", colName, satDbName)
+		}
+	}
+
+	return &esatDefinition, nil
+}
+
+//ExtractDbEntityName extract entity type, name and revision from data table name
+//example: hub_tax_invoice_rev0 is hub, TaxInvoice, revision 0
+func ExtractDbEntityName(dbTableName string) (
+	definition.EntityType, string, int, error) {
+
+	//validate prefix
+	var prefix string
+	var entityType definition.EntityType
+	if strings.HasPrefix(dbTableName, "hub_") {
+		prefix = "hub_"
+		entityType = definition.HUB
+
+	} else if strings.HasPrefix(dbTableName, "link_") {
+		prefix = "link_"
+		entityType = definition.LINK
+
+	} else if strings.HasPrefix(dbTableName, "sat_") {
+		prefix = "sat_"
+		entityType = definition.SATELITE
+
+	} else if strings.HasPrefix(dbTableName, "esat_") {
+		prefix = "esat_"
+		entityType = definition.EFFECTIVITY_SATELITE
+
+	} else if strings.HasPrefix(dbTableName, "ref_") {
+		prefix = "ref_"
+		entityType = definition.REFERENCE
+
+	} else if strings.HasPrefix(dbTableName, "nhl_") {
+		prefix = "nhl_"
+		entityType = definition.NON_HISTORIZED_LINK
+
+	} else {
+		return 0, "", 0, fmt.Errorf("Unrecognized db table for data vault: %s", dbTableName)
+	}
+
+	//extract and validate entity name
+	trimHeader := strings.TrimPrefix(dbTableName, prefix)
+	raws := strings.Split(trimHeader, "_rev")
+	if len(raws) != 2 {
+		return 0, "", 0, fmt.Errorf("Invalid data vault db table name format: %s",
+			dbTableName)
+	}
+	name := stringtool.SnakeToCamelCase(raws[0])
+
+	//validate suffix (_rev)
+	rev, revErr := strconv.Atoi(raws[1])
+	if revErr != nil {
+		return 0, "", 0, fmt.Errorf("Invalid revision value %s from table name %s",
+			raws[1], dbTableName)
+	}
+
+	return entityType, name, rev, nil
+}
+
+//ParseReferenceDefinition convert reference data table definition into reference definition
+func ParseReferenceDefinition(refName string, revision int, tableDef *rdbmstool.TableDefinition) (
+	*definition.ReferenceDefinition, error) {
+
+	refDbName := definition.ReferenceTableName(refName, revision)
+
+	refDefinition := definition.ReferenceDefinition{
+		Name:       refName,
+		Revision:   revision,
+		Keys:       []string{},
+		Attributes: []definition.SateliteAttributeDefinition{}}
+
+	hasLoadDate := false
+	hasRecordSource := false
+	for _, col := range tableDef.Columns {
+		if col.DataType == rdbmstool.DATETIME && strings.Compare(col.Name, definition.LOAD_DATE) == 0 {
+			hasLoadDate = true
+		} else if col.DataType == rdbmstool.CHAR && strings.Compare(col.Name, definition.RECORD_SOURCE) == 0 {
+			hasRecordSource = true
+		} else {
+			attr, attrErr := parseAttributeColumn(col)
+			if attrErr != nil {
+				return nil, fmt.Errorf("Unsupported datatype for Reference definition: %s",
+					col.DataType.String())
+			}
+
+			refDefinition.Attributes = append(refDefinition.Attributes, *attr)
+		}
+	}
+
+	if len(refDefinition.Attributes) == 0 {
+		return nil, fmt.Errorf("Data table %s not found in database", refDbName)
+	}
+
+	if !hasLoadDate {
+		return nil, fmt.Errorf("Load date column not found in reference %s", refDbName)
+	}
+
+	if !hasRecordSource {
+		return nil, fmt.Errorf("Record source column not found in reference %s", refDbName)
+	}
+
+	for _, pk := range tableDef.PrimaryKey {
+		refDefinition.Keys = append(refDefinition.Keys, stringtool.SnakeToCamelCase(pk))
+	}
+
+	if len(refDefinition.Keys) == 0 {
+		return nil, fmt.Errorf("Primary key not found in reference %s", refDbName)
+	}
+
+	return &refDefinition, nil
+}
+
+//ParseNonHistorizedLinkDefinition convert non-historized link data table definition
+//into non-historized link definition
+func ParseNonHistorizedLinkDefinition(linkName string, revision int, tableDef *rdbmstool.TableDefinition) (
+	*definition.NonHistorizedLinkDefinition, error) {
+
+	linkDbName := definition.NonHistorizedLinkTableName(linkName, revision)
+
+	nhlDefinition := definition.NonHistorizedLinkDefinition{
+		Name:          linkName,
+		Revision:      revision,
+		HubReferences: []definition.HubReference{},
+		Attributes:    []definition.SateliteAttributeDefinition{}}
+
+	//hub reference column(s) are identified through FK
+	refColumns := make(map[string]bool)
+	for _, fk := range tableDef.ForiegnKeys {
+		if len(fk.Columns) != 1 {
+			return nil, fmt.Errorf("Non-historized link entity only support one pair of FK reference"+
+				" but found %s has %d pair instead", fk.Name, len(fk.Columns))
+		}
+
+		entityType, name, refRevision, extractErr := ExtractDbEntityName(fk.ReferenceTableName)
+		if extractErr != nil {
+			return nil, extractErr
+		}
+
+		if entityType != definition.HUB {
+			return nil, fmt.Errorf("Non-historized link %s FK only allow to refer hub but found %s",
+				linkDbName, fk.ReferenceTableName)
+		}
+
+		hubRef, refErr := parseHubReference(linkDbName, name, refRevision, fk.Columns[0].ColumnName)
+		if refErr != nil {
+			return nil, refErr
+		}
+
+		nhlDefinition.HubReferences = append(nhlDefinition.HubReferences, *hubRef)
+		refColumns[fk.Columns[0].ColumnName] = true
+	}
+
+	if len(nhlDefinition.HubReferences) < 2 {
+		return nil, fmt.Errorf("invalid non-historized link entity: atleast two hub references "+
+			"must be presense but found %d reference only", len(nhlDefinition.HubReferences))
+	}
+
+	hasHashKey := false
+	hasLoadDate := false
+	hasRecordSource := false
+	for _, col := range tableDef.Columns {
+		if refColumns[col.Name] {
+			continue
+		}
+
+		if col.DataType == rdbmstool.CHAR && strings.Compare(col.Name, makeDVHashKey(linkName)) == 0 {
+			hasHashKey = true
+		} else if col.DataType == rdbmstool.DATETIME && strings.Compare(col.Name, definition.LOAD_DATE) == 0 {
+			hasLoadDate = true
+		} else if col.DataType == rdbmstool.CHAR && strings.Compare(col.Name, definition.RECORD_SOURCE) == 0 {
+			hasRecordSource = true
+		} else {
+			attr, attrErr := parseAttributeColumn(col)
+			if attrErr != nil {
+				return nil, fmt.Errorf("Unsupported datatype for Non-historized link definition: %s",
+					col.DataType.String())
+			}
+
+			nhlDefinition.Attributes = append(nhlDefinition.Attributes, *attr)
+		}
+	}
+
+	if !hasHashKey {
+		return nil, fmt.Errorf("Hash key column not found in non-historized link %s", linkDbName)
+	}
+
+	if !hasLoadDate {
+		return nil, fmt.Errorf("Load date column not found in non-historized link %s", linkDbName)
+	}
+
+	if !hasRecordSource {
+		return nil, fmt.Errorf("Record source column not found in non-historized link %s", linkDbName)
+	}
+
+	return &nhlDefinition, nil
+}
+
+//parseHubReference convert FK column of link into hub reference; column name
+//other than hub hash key is prefixed with role name
+func parseHubReference(linkDbName string, hubName string, revision int, fkColName string) (
+	*definition.HubReference, error) {
+	hubRef := definition.HubReference{
+		HubName:  hubName,
+		Revision: revision}
+
+	if strings.Compare(fkColName, hubRef.GetHashKey()) != 0 {
+		if !strings.HasSuffix(fkColName, "_"+hubRef.GetHashKey()) {
+			return nil, fmt.Errorf("Link %s FK column %s is not a valid hash key column of hub %s",
+				linkDbName, fkColName, hubName)
+		}
+
+		hubRef.Role = stringtool.SnakeToCamelCase(
+			strings.TrimSuffix(fkColName, "_"+hubRef.GetHashKey()))
+	}
+
+	return &hubRef, nil
+}
+
+//parseHashAlgorithm find hash algorithm which produce hash key as long as given CHAR column
+func parseHashAlgorithm(dbTableName string, col rdbmstool.ColumnDefinition) (hashkey.Algorithm, error) {
+	for _, algorithm := range []hashkey.Algorithm{hashkey.MD5, hashkey.SHA1, hashkey.SHA256} {
+		if col.Length == algorithm.Length() {
+			return algorithm, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Hash key column %s of %s has length %d which match no hash algorithm",
+		col.Name, dbTableName, col.Length)
+}
+
+//parseAttributeColumn convert data table column into attribute definition
+func parseAttributeColumn(col rdbmstool.ColumnDefinition) (*definition.SateliteAttributeDefinition, error) {
+	attr := definition.SateliteAttributeDefinition{
+		Name:       stringtool.SnakeToCamelCase(col.Name),
+		DataType:   col.DataType,
+		IsNullable: col.IsNullable}
+
+	switch col.DataType {
+	case rdbmstool.BOOLEAN, rdbmstool.DATE, rdbmstool.DATETIME, rdbmstool.FLOAT, rdbmstool.TEXT:
+		break
+	case rdbmstool.CHAR, rdbmstool.INTEGER, rdbmstool.VARCHAR:
+		attr.Length = col.Length
+		break
+	case rdbmstool.DECIMAL:
+		attr.Length = col.Length
+		attr.DecimalPrecision = col.DecimalPrecision
+		break
+	default:
+		return nil, fmt.Errorf("Unsupported datatype for attribute: %s", col.DataType.String())
+	}
+
+	return &attr, nil
+}
+
+func parseBusinessKeyColumn(col rdbmstool.ColumnDefinition) (*definition.BusinessKeyDefinition, error) {
+	bk := definition.BusinessKeyDefinition{
+		Name:     stringtool.SnakeToCamelCase(col.Name),
+		DataType: col.DataType}
+
+	switch col.DataType {
+	case rdbmstool.CHAR, rdbmstool.VARCHAR:
+		bk.Length = col.Length
+		break
+	case rdbmstool.INTEGER, rdbmstool.DATE:
+		break
+	default:
+		return nil, fmt.Errorf(
+			"Unsupported datatype (%s) parse into hub business key %s", col.DataType.String(), col.Name)
+	}
+
+	return &bk, nil
+}
+
+func makeDVHashKey(entityName string) string {
+	return definition.HashKeyColumnName(entityName)
+}
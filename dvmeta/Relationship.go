@@ -0,0 +1,163 @@
+package dvmeta
+
+import (
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/rdbmstool"
+)
+
+//LinkedTableFinder list data table name(s) which has foreign key refer to given data table
+type LinkedTableFinder func(dbHandler rdbmstool.DbHandlerProxy, tableName string) ([]string, error)
+
+//ResolveRelationship search all direct related links and satelites for provided hub;
+//entities definition are read through metaReader while related data tables are
+//discovered through findLinkedTables
+func ResolveRelationship(metaReader DataVaultMetaReader, findLinkedTables LinkedTableFinder,
+	dbHandler rdbmstool.DbHandlerProxy, hubName string, hubRevision int) (*HubRelationship, error) {
+	//get related satalites which refer to specified hub
+	hubTableName := definition.HubTableName(hubName, hubRevision)
+	tables, linkErr := findLinkedTables(dbHandler, hubTableName)
+	if linkErr != nil {
+		return nil, linkErr
+	}
+
+	result := HubRelationship{
+		HubName:            hubName,
+		HubRevision:        hubRevision,
+		Satelites:          []definition.SateliteDefinition{},
+		Links:              []HubLinkRelationship{},
+		NonHistorizedLinks: []definition.NonHistorizedLinkDefinition{},
+	}
+	//link may refer to the same hub more than once (same-as or hierarchical link)
+	visited := make(map[string]bool)
+	for _, tableName := range tables {
+		if visited[tableName] {
+			continue
+		}
+		visited[tableName] = true
+
+		entityType, name, rev, err := ExtractDbEntityName(tableName)
+		if err != nil {
+			return nil, err
+		}
+
+		switch entityType {
+		case definition.SATELITE:
+			satDef, satErr := metaReader.GetSateliteDefinition(name, rev, dbHandler)
+			if satErr != nil {
+				return nil, satErr
+			}
+			result.Satelites = append(result.Satelites, *satDef)
+			break
+		case definition.LINK:
+			linkDef, linkErr := metaReader.GetLinkDefinition(name, rev, dbHandler)
+			if linkErr != nil {
+				return nil, linkErr
+			}
+
+			//append hub link relationship
+			hubLink, hubLinkErr := resolveHubLinkRelationship(metaReader, findLinkedTables,
+				dbHandler, linkDef, hubName, hubRevision)
+			if hubLinkErr != nil {
+				return nil, hubLinkErr
+			}
+
+			result.Links = append(result.Links, *hubLink)
+			break
+		case definition.NON_HISTORIZED_LINK:
+			nhlDef, nhlErr := metaReader.GetNonHistorizedLinkDefinition(name, rev, dbHandler)
+			if nhlErr != nil {
+				return nil, nhlErr
+			}
+
+			result.NonHistorizedLinks = append(result.NonHistorizedLinks, *nhlDef)
+			break
+		}
+	}
+
+	return &result, nil
+}
+
+func resolveHubLinkRelationship(metaReader DataVaultMetaReader, findLinkedTables LinkedTableFinder,
+	dbHandler rdbmstool.DbHandlerProxy, linkDef *definition.LinkDefinition,
+	hubName string, hubRevision int) (*HubLinkRelationship, error) {
+
+	hubLink := HubLinkRelationship{
+		Definition:           linkDef,
+		Hubs:                 []definition.HubDefinition{},
+		Satelites:            []definition.SateliteDefinition{},
+		LinkSatelites:        []definition.SateliteDefinition{},
+		EffectivitySatelites: []definition.EffectivitySateliteDefinition{},
+	}
+
+	//search all satelite(s) attached to link
+	linkTables, linkTableErr := findLinkedTables(dbHandler, linkDef.GetDbTableName())
+	if linkTableErr != nil {
+		return nil, linkTableErr
+	}
+
+	for _, table := range linkTables {
+		entityType, name, rev, err := ExtractDbEntityName(table)
+		if err != nil {
+			continue //skip if it is not a valid format data vault db table
+		}
+
+		switch entityType {
+		case definition.SATELITE:
+			satDef, satErr := metaReader.GetSateliteDefinition(name, rev, dbHandler)
+			if satErr != nil {
+				return nil, satErr
+			}
+
+			hubLink.LinkSatelites = append(hubLink.LinkSatelites, *satDef)
+			break
+		case definition.EFFECTIVITY_SATELITE:
+			esatDef, esatErr := metaReader.GetEffectivitySateliteDefinition(name, rev, dbHandler)
+			if esatErr != nil {
+				return nil, esatErr
+			}
+
+			hubLink.EffectivitySatelites = append(hubLink.EffectivitySatelites, *esatDef)
+			break
+		}
+	}
+
+	expectedTableName := definition.HubTableName(hubName, hubRevision)
+
+	for _, hubRef := range linkDef.HubReferences {
+
+		tmpTableName := definition.HubTableName(hubRef.HubName, hubRef.Revision)
+		//append if it is not reference to entry point's hub name
+		if strings.Compare(tmpTableName, expectedTableName) != 0 {
+			//made a hub definition
+			hubDef, hubErr := metaReader.GetHubDefinition(hubRef.HubName, hubRef.Revision, dbHandler)
+			if hubErr != nil {
+				return nil, hubErr
+			}
+			hubLink.Hubs = append(hubLink.Hubs, *hubDef)
+
+			//search all related satelite(s) for linked hub
+			tables, tableErr := findLinkedTables(dbHandler, hubRef.GetDbTableName())
+			if tableErr != nil {
+				return nil, tableErr
+			}
+
+			for _, table := range tables {
+				entityType, name, rev, err := ExtractDbEntityName(table)
+				if err != nil || entityType != definition.SATELITE {
+					continue //skip if it is not a valid format satelite db table
+				}
+
+				satDef, satErr := metaReader.GetSateliteDefinition(name, rev, dbHandler)
+				if satErr != nil {
+					return nil, satErr
+				}
+
+				hubLink.Satelites = append(hubLink.Satelites, *satDef)
+			}
+		}
+	}
+
+	return &hubLink, nil
+}
@@ -0,0 +1,435 @@
+package dvmeta
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//DiffType category of difference between data vault definition and database
+type DiffType uint8
+
+//List of difference category
+const (
+	//MISSING_ENTITY is entity defined but not found in database
+	MISSING_ENTITY DiffType = iota + 1
+	//EXTRA_ENTITY is entity found in database but not defined
+	EXTRA_ENTITY DiffType = iota + 1
+	//MISSING_COLUMN is attribute or business key defined but not found in database
+	MISSING_COLUMN DiffType = iota + 1
+	//EXTRA_COLUMN is attribute or business key found in database but not defined
+	EXTRA_COLUMN DiffType = iota + 1
+	//COLUMN_MISMATCH is column which datatype, length or nullability is different
+	COLUMN_MISMATCH DiffType = iota + 1
+	//BROKEN_REFERENCE is hub or link reference which is different
+	BROKEN_REFERENCE DiffType = iota + 1
+	//UNREADABLE_ENTITY is entity found in database but fail to be read, e.g. its reference is broken
+	UNREADABLE_ENTITY DiffType = iota + 1
+)
+
+func (diffType DiffType) String() string {
+	if diffType == MISSING_ENTITY {
+		return "missing entity"
+	} else if diffType == EXTRA_ENTITY {
+		return "extra entity"
+	} else if diffType == MISSING_COLUMN {
+		return "missing column"
+	} else if diffType == EXTRA_COLUMN {
+		return "extra column"
+	} else if diffType == COLUMN_MISMATCH {
+		return "column mismatch"
+	} else if diffType == BROKEN_REFERENCE {
+		return "broken reference"
+	} else if diffType == UNREADABLE_ENTITY {
+		return "unreadable entity"
+	}
+
+	return "unknown"
+}
+
+//SchemaDifference is one difference found between data vault definition and database;
+//Column is empty if difference is about entity itself
+type SchemaDifference struct {
+	Type        DiffType
+	EntityType  definition.EntityType
+	Name        string
+	Revision    int
+	Column      string
+	Description string
+}
+
+func (difference SchemaDifference) String() string {
+	return fmt.Sprintf("%s %s revision %d: %s",
+		difference.EntityType, difference.Name, difference.Revision, difference.Description)
+}
+
+//SchemaDiff is result of comparing data vault definition with database;
+//Missing is entities to be created so database converge to definition;
+//Warnings is data table(s) skipped as their name is not valid data vault table name
+type SchemaDiff struct {
+	Differences []SchemaDifference
+	Missing     definition.DataVaultDefinition
+	Warnings    []string
+}
+
+//DiffDataVault compare data vault definition with hubs, links and satelites
+//read from database by meta reader; entity which fail to be read is reported as difference,
+//and data table which fail to be listed is reported as warning, so the rest of entities are still compared
+func DiffDataVault(metaReader DataVaultMetaReader, dbHandler rdbmstool.DbHandlerProxy,
+	dvDef *definition.DataVaultDefinition) (*SchemaDiff, error) {
+
+	if dvDef == nil {
+		return nil, errors.New("Data vault definition cannot be null")
+	}
+
+	if validateErr := dvDef.Validate(); validateErr != nil {
+		return nil, validateErr
+	}
+
+	//data table which fail to be listed is not data vault entity, so it is only warned
+	listErr := ReadError{}
+	hubInfos, hubErr := metaReader.GetAllHubs(dbHandler)
+	if mergeErr := listErr.merge(hubErr); mergeErr != nil {
+		return nil, mergeErr
+	}
+
+	linkInfos, linkErr := metaReader.GetAllLinks(dbHandler)
+	if mergeErr := listErr.merge(linkErr); mergeErr != nil {
+		return nil, mergeErr
+	}
+
+	satInfos, satErr := metaReader.GetAllSatelites(dbHandler)
+	if mergeErr := listErr.merge(satErr); mergeErr != nil {
+		return nil, mergeErr
+	}
+
+	diff := SchemaDiff{
+		Missing:  definition.DataVaultDefinition{HashAlgorithm: dvDef.HashAlgorithm},
+		Warnings: listErr.Problems}
+
+	//hubs
+	definedHubs := make(map[string]bool)
+	liveHubs := make(map[string]bool)
+	for _, info := range hubInfos {
+		liveHubs[definition.HubTableName(info.Name, info.Revision)] = true
+	}
+	for _, hubDef := range dvDef.Hubs {
+		definedHubs[hubDef.GetDbTableName()] = true
+
+		if !liveHubs[hubDef.GetDbTableName()] {
+			diff.add(MISSING_ENTITY, definition.HUB, hubDef.Name, hubDef.Revision, "",
+				"not found in database")
+			diff.Missing.Hubs = append(diff.Missing.Hubs, hubDef)
+			continue
+		}
+
+		liveDef, liveErr := metaReader.GetHubDefinition(hubDef.Name, hubDef.Revision, dbHandler)
+		if liveErr != nil {
+			diff.add(UNREADABLE_ENTITY, definition.HUB, hubDef.Name, hubDef.Revision, "",
+				"fail to read from database: %s", liveErr.Error())
+			continue
+		}
+
+		if dvDef.HashAlgorithm > 0 {
+			hubDef.HashAlgorithm = dvDef.HashAlgorithm
+		}
+
+		if compareErr := diff.compareHub(&hubDef, liveDef); compareErr != nil {
+			return nil, compareErr
+		}
+	}
+	for _, info := range hubInfos {
+		if !definedHubs[definition.HubTableName(info.Name, info.Revision)] {
+			diff.add(EXTRA_ENTITY, definition.HUB, info.Name, info.Revision, "", "not defined")
+		}
+	}
+
+	//links
+	definedLinks := make(map[string]bool)
+	liveLinks := make(map[string]bool)
+	for _, info := range linkInfos {
+		liveLinks[definition.LinkTableName(info.Name, info.Revision)] = true
+	}
+	for _, linkDef := range dvDef.Links {
+		definedLinks[linkDef.GetDbTableName()] = true
+
+		if !liveLinks[linkDef.GetDbTableName()] {
+			diff.add(MISSING_ENTITY, definition.LINK, linkDef.Name, linkDef.Revision, "",
+				"not found in database")
+			diff.Missing.Links = append(diff.Missing.Links, linkDef)
+			continue
+		}
+
+		liveDef, liveErr := metaReader.GetLinkDefinition(linkDef.Name, linkDef.Revision, dbHandler)
+		if liveErr != nil {
+			diff.add(UNREADABLE_ENTITY, definition.LINK, linkDef.Name, linkDef.Revision, "",
+				"fail to read from database: %s", liveErr.Error())
+			continue
+		}
+
+		if dvDef.HashAlgorithm > 0 {
+			linkDef.HashAlgorithm = dvDef.HashAlgorithm
+		}
+
+		diff.compareLink(&linkDef, liveDef)
+	}
+	for _, info := range linkInfos {
+		if !definedLinks[definition.LinkTableName(info.Name, info.Revision)] {
+			diff.add(EXTRA_ENTITY, definition.LINK, info.Name, info.Revision, "", "not defined")
+		}
+	}
+
+	//satelites
+	definedSatelites := make(map[string]bool)
+	liveSatelites := make(map[string]bool)
+	for _, info := range satInfos {
+		liveSatelites[definition.SateliteTableName(info.Name, info.Revision)] = true
+	}
+	for _, satDef := range dvDef.Satelites {
+		definedSatelites[satDef.GetDbTableName()] = true
+
+		if !liveSatelites[satDef.GetDbTableName()] {
+			diff.add(MISSING_ENTITY, definition.SATELITE, satDef.Name, satDef.Revision, "",
+				"not found in database")
+			diff.Missing.Satelites = append(diff.Missing.Satelites, satDef)
+			continue
+		}
+
+		liveDef, liveErr := metaReader.GetSateliteDefinition(satDef.Name, satDef.Revision, dbHandler)
+		if liveErr != nil {
+			diff.add(UNREADABLE_ENTITY, definition.SATELITE, satDef.Name, satDef.Revision, "",
+				"fail to read from database: %s", liveErr.Error())
+			continue
+		}
+
+		if dvDef.HashAlgorithm > 0 {
+			satDef.HashAlgorithm = dvDef.HashAlgorithm
+		}
+
+		diff.compareSatelite(&satDef, liveDef)
+	}
+	for _, info := range satInfos {
+		if !definedSatelites[definition.SateliteTableName(info.Name, info.Revision)] {
+			diff.add(EXTRA_ENTITY, definition.SATELITE, info.Name, info.Revision, "", "not defined")
+		}
+	}
+
+	return &diff, nil
+}
+
+//GeneratePlan is to generate SQL statements which create missing entities, so database
+//converge to definition; extra entities are left as it is; existing data table is never
+//altered as it keeps history, so any column or reference difference is reported as error,
+//and should be resolved by new revision instead
+func (diff *SchemaDiff) GeneratePlan(sqlDialect dialect.Dialect) ([]string, error) {
+	var unresolved []string
+	for _, difference := range diff.Differences {
+		if difference.Type != MISSING_ENTITY && difference.Type != EXTRA_ENTITY {
+			unresolved = append(unresolved, difference.String())
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("%d difference(s) require new revision:\n- %s",
+			len(unresolved), strings.Join(unresolved, "\n- "))
+	}
+
+	//missing satelite may refer to hub or link which already exists in database
+	return diff.Missing.GenerateDialectSQL(sqlDialect)
+}
+
+func (diff *SchemaDiff) add(diffType DiffType, entityType definition.EntityType,
+	name string, revision int, column string, format string, args ...interface{}) {
+	diff.Differences = append(diff.Differences, SchemaDifference{
+		Type:        diffType,
+		EntityType:  entityType,
+		Name:        name,
+		Revision:    revision,
+		Column:      column,
+		Description: fmt.Sprintf(format, args...)})
+}
+
+func (diff *SchemaDiff) compareHub(hubDef *definition.HubDefinition, liveDef *definition.HubDefinition) error {
+	var definedCols, liveCols []rdbmstool.ColumnDefinition
+	for _, bk := range hubDef.BusinessKeys {
+		col, err := bk.GetBusinessKeyColumn()
+		if err != nil {
+			return err
+		}
+		definedCols = append(definedCols, col)
+	}
+
+	for _, bk := range liveDef.BusinessKeys {
+		col, err := bk.GetBusinessKeyColumn()
+		if err != nil {
+			return err
+		}
+		liveCols = append(liveCols, col)
+	}
+
+	diff.compareHashLength(definition.HUB, hubDef.Name, hubDef.Revision, hubDef.GetHashKey(),
+		hubDef.HashAlgorithm, liveDef.HashAlgorithm)
+	diff.compareColumns(definition.HUB, hubDef.Name, hubDef.Revision, "business key",
+		definedCols, liveCols)
+
+	return nil
+}
+
+func (diff *SchemaDiff) compareLink(linkDef *definition.LinkDefinition, liveDef *definition.LinkDefinition) {
+	diff.compareHashLength(definition.LINK, linkDef.Name, linkDef.Revision, linkDef.GetHashKey(),
+		linkDef.HashAlgorithm, liveDef.HashAlgorithm)
+
+	liveRefs := make(map[string]string)
+	for _, hubRef := range liveDef.HubReferences {
+		liveRefs[hubRef.GetColumnName()] = hubRef.GetDbTableName()
+	}
+
+	for _, hubRef := range linkDef.HubReferences {
+		liveTable, exists := liveRefs[hubRef.GetColumnName()]
+		if !exists {
+			diff.add(BROKEN_REFERENCE, definition.LINK, linkDef.Name, linkDef.Revision,
+				hubRef.GetColumnName(), "reference to %s not found in database", hubRef.GetDbTableName())
+		} else if strings.Compare(liveTable, hubRef.GetDbTableName()) != 0 {
+			diff.add(BROKEN_REFERENCE, definition.LINK, linkDef.Name, linkDef.Revision,
+				hubRef.GetColumnName(), "%s refers to %s in database, %s in definition",
+				hubRef.GetColumnName(), liveTable, hubRef.GetDbTableName())
+		}
+		delete(liveRefs, hubRef.GetColumnName())
+	}
+
+	for _, hubRef := range liveDef.HubReferences {
+		if _, exists := liveRefs[hubRef.GetColumnName()]; exists {
+			diff.add(BROKEN_REFERENCE, definition.LINK, linkDef.Name, linkDef.Revision,
+				hubRef.GetColumnName(), "reference to %s is not defined", hubRef.GetDbTableName())
+		}
+	}
+}
+
+func (diff *SchemaDiff) compareSatelite(satDef *definition.SateliteDefinition, liveDef *definition.SateliteDefinition) {
+	if strings.Compare(satDef.GetParentDbTableName(), liveDef.GetParentDbTableName()) != 0 {
+		diff.add(BROKEN_REFERENCE, definition.SATELITE, satDef.Name, satDef.Revision,
+			liveDef.GetParentHashKey(), "refers to %s in database, %s in definition",
+			liveDef.GetParentDbTableName(), satDef.GetParentDbTableName())
+	}
+
+	diff.compareHashLength(definition.SATELITE, satDef.Name, satDef.Revision, satDef.GetParentHashKey(),
+		satDef.HashAlgorithm, liveDef.HashAlgorithm)
+
+	if satDef.HasHashDiff != liveDef.HasHashDiff {
+		diff.add(COLUMN_MISMATCH, definition.SATELITE, satDef.Name, satDef.Revision,
+			definition.HASH_DIFF, "hash diff is %t in database, %t in definition",
+			liveDef.HasHashDiff, satDef.HasHashDiff)
+	} else if satDef.HasHashDiff {
+		diff.compareHashLength(definition.SATELITE, satDef.Name, satDef.Revision, definition.HASH_DIFF,
+			satDef.HashAlgorithm, liveDef.HashAlgorithm)
+	}
+
+	if !isSameNames(satDef.MultiActiveKeys, liveDef.MultiActiveKeys) {
+		diff.add(COLUMN_MISMATCH, definition.SATELITE, satDef.Name, satDef.Revision, "",
+			"multi-active keys are %v in database, %v in definition",
+			liveDef.MultiActiveKeys, satDef.MultiActiveKeys)
+	}
+
+	var definedCols, liveCols []rdbmstool.ColumnDefinition
+	for index := range satDef.Attributes {
+		definedCols = append(definedCols, satDef.Attributes[index].GetAttributeColumn())
+	}
+
+	for index := range liveDef.Attributes {
+		liveCols = append(liveCols, liveDef.Attributes[index].GetAttributeColumn())
+	}
+
+	diff.compareColumns(definition.SATELITE, satDef.Name, satDef.Revision, "attribute",
+		definedCols, liveCols)
+}
+
+//compareHashLength compare length of hash key (or hash diff) column, which is decided by hash algorithm
+func (diff *SchemaDiff) compareHashLength(entityType definition.EntityType, name string, revision int,
+	column string, algorithm hashkey.Algorithm, liveAlgorithm hashkey.Algorithm) {
+
+	if algorithm.Length() != liveAlgorithm.Length() {
+		diff.add(COLUMN_MISMATCH, entityType, name, revision, column,
+			"%s is CHAR(%d) in database, CHAR(%d) in definition",
+			column, liveAlgorithm.Length(), algorithm.Length())
+	}
+}
+
+//compareColumns compare columns by name; length is only compared for datatype which has length
+func (diff *SchemaDiff) compareColumns(entityType definition.EntityType, name string, revision int,
+	kind string, definedCols []rdbmstool.ColumnDefinition, liveCols []rdbmstool.ColumnDefinition) {
+
+	liveMap := make(map[string]rdbmstool.ColumnDefinition)
+	for _, col := range liveCols {
+		liveMap[col.Name] = col
+	}
+
+	for _, col := range definedCols {
+		liveCol, exists := liveMap[col.Name]
+		if !exists {
+			diff.add(MISSING_COLUMN, entityType, name, revision, col.Name,
+				"%s %s not found in database", kind, col.Name)
+			continue
+		}
+
+		if formatColumnType(col) != formatColumnType(liveCol) {
+			diff.add(COLUMN_MISMATCH, entityType, name, revision, col.Name,
+				"%s %s is %s in database, %s in definition",
+				kind, col.Name, formatColumnType(liveCol), formatColumnType(col))
+		}
+		delete(liveMap, col.Name)
+	}
+
+	for _, col := range liveCols {
+		if _, exists := liveMap[col.Name]; exists {
+			diff.add(EXTRA_COLUMN, entityType, name, revision, col.Name,
+				"%s %s is not defined", kind, col.Name)
+		}
+	}
+}
+
+func formatColumnType(col rdbmstool.ColumnDefinition) string {
+	result := col.DataType.String()
+
+	switch col.DataType {
+	case rdbmstool.CHAR, rdbmstool.VARCHAR:
+		result = fmt.Sprintf("%s(%d)", result, col.Length)
+		break
+	case rdbmstool.DECIMAL:
+		result = fmt.Sprintf("%s(%d,%d)", result, col.Length, col.DecimalPrecision)
+		break
+	}
+
+	if col.IsNullable {
+		return result + " NULL"
+	}
+
+	return result + " NOT NULL"
+}
+
+func isSameNames(names []string, others []string) bool {
+	if len(names) != len(others) {
+		return false
+	}
+
+	for _, name := range names {
+		found := false
+		for _, other := range others {
+			if strings.Compare(stringtool.ToSnakeCase(name), stringtool.ToSnakeCase(other)) == 0 {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
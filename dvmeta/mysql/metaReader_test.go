@@ -138,18 +138,18 @@ func TestGetAllEntities(t *testing.T) {
 	metaReader := MetaReader{
 		DbName: "test"}
 
-	hubs := metaReader.GetAllHubs(transaction)
-	if len(hubs) == 0 {
+	hubs, hubsErr := metaReader.GetAllHubs(transaction)
+	if hubsErr != nil || len(hubs) == 0 {
 		t.Error("Expect hubs count more than 0")
 	}
 
-	links := metaReader.GetAllLinks(transaction)
-	if len(links) == 0 {
+	links, linksErr := metaReader.GetAllLinks(transaction)
+	if linksErr != nil || len(links) == 0 {
 		t.Error("Expect links count more than 0")
 	}
 
-	sats := metaReader.GetAllSatelites(transaction)
-	if len(sats) == 0 {
+	sats, satsErr := metaReader.GetAllSatelites(transaction)
+	if satsErr != nil || len(sats) == 0 {
 		t.Error("Expect satelites count more than 0")
 	}
 
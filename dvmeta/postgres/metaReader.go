@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/rdbmstool"
+)
+
+//MetaReader implementation of DataVaultMetaReader for PostgreSQL;
+//SchemaName is database schema which host data vault, example: public
+type MetaReader struct {
+	SchemaName string
+}
+
+//GetHubDefinition to get hub metainfo based on hub name and its revision number in transaction mode
+//*must provide database transaction handler
+//example hub name: TaxInvoice, revision: 0
+func (metaReader *MetaReader) GetHubDefinition(
+	hubName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.HubDefinition, error) {
+
+	hubDbName := definition.HubTableName(hubName, revision)
+
+	tableDef, defErr := getTableDefinition(dbHandler, metaReader.SchemaName, hubDbName)
+	if defErr != nil {
+		return nil, defErr
+	}
+
+	return dvmeta.ParseHubDefinition(hubName, revision, tableDef)
+}
+
+//GetLinkDefinition to get link metainfo based on link name and its revision number
+func (metaReader *MetaReader) GetLinkDefinition(
+	linkName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.LinkDefinition, error) {
+
+	linkDbName := definition.LinkTableName(linkName, revision)
+
+	//read all FK records
+	tableDef, tableErr := getTableDefinition(dbHandler, metaReader.SchemaName, linkDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseLinkDefinition(linkName, revision, tableDef)
+}
+
+//GetSateliteDefinition get satelite metainfo based on satelite name and its revision number
+func (metaReader *MetaReader) GetSateliteDefinition(
+	satName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.SateliteDefinition, error) {
+
+	satDbName := definition.SateliteTableName(satName, revision)
+
+	//read all FK records
+	tableDef, tableErr := getTableDefinition(dbHandler, metaReader.SchemaName, satDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseSateliteDefinition(satName, revision, tableDef)
+}
+
+//GetEffectivitySateliteDefinition get effectivity satelite metainfo based on its name and revision number
+func (metaReader *MetaReader) GetEffectivitySateliteDefinition(
+	satName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.EffectivitySateliteDefinition, error) {
+
+	satDbName := definition.EffectivitySateliteTableName(satName, revision)
+
+	tableDef, tableErr := getTableDefinition(dbHandler, metaReader.SchemaName, satDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseEffectivitySateliteDefinition(satName, revision, tableDef)
+}
+
+//GetReferenceDefinition get reference table metainfo based on reference name and its revision number
+func (metaReader *MetaReader) GetReferenceDefinition(
+	refName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.ReferenceDefinition, error) {
+
+	refDbName := definition.ReferenceTableName(refName, revision)
+
+	tableDef, tableErr := getTableDefinition(dbHandler, metaReader.SchemaName, refDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseReferenceDefinition(refName, revision, tableDef)
+}
+
+//GetNonHistorizedLinkDefinition get non-historized link metainfo based on link name and its revision number
+func (metaReader *MetaReader) GetNonHistorizedLinkDefinition(
+	linkName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.NonHistorizedLinkDefinition, error) {
+
+	linkDbName := definition.NonHistorizedLinkTableName(linkName, revision)
+
+	tableDef, tableErr := getTableDefinition(dbHandler, metaReader.SchemaName, linkDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseNonHistorizedLinkDefinition(linkName, revision, tableDef)
+}
+
+//GetAllHubs list all available hub(s) entity in given database schema
+func (metaReader *MetaReader) GetAllHubs(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error) {
+	return getTableName(dbHandler, metaReader.SchemaName, "hub\\_%")
+}
+
+//GetAllLinks list all available link(s) entity in given database schema
+func (metaReader *MetaReader) GetAllLinks(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error) {
+	return getTableName(dbHandler, metaReader.SchemaName, "link\\_%")
+}
+
+//GetAllSatelites list all available satelite(s) entity in given database schema
+func (metaReader *MetaReader) GetAllSatelites(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error) {
+	return getTableName(dbHandler, metaReader.SchemaName, "sat\\_%")
+}
+
+//SearchEntities list all available data vault entities based on given keyword;
+//data table which is not data vault entity is skipped
+func (metaReader *MetaReader) SearchEntities(dbHandler rdbmstool.DbHandlerProxy, searchKeyword string) []dvmeta.EntityInfo {
+	x, err := getTableName(dbHandler, metaReader.SchemaName, "%"+searchKeyword+"%")
+
+	if _, isReadErr := err.(*dvmeta.ReadError); err != nil && !isReadErr {
+		return []dvmeta.EntityInfo{}
+	}
+
+	return x
+}
+
+//GetDataVaultDefinition read all hubs, links and satelites (all revisions) into data vault definition
+func (metaReader *MetaReader) GetDataVaultDefinition(dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.DataVaultDefinition, error) {
+	return dvmeta.ReadDataVault(metaReader, dbHandler)
+}
+
+//GetRelationship search all direct related links and satelites for provided hub
+func (metaReader *MetaReader) GetRelationship(dbHandler rdbmstool.DbHandlerProxy, hubName string, hubRevision int) (*dvmeta.HubRelationship, error) {
+	return dvmeta.ResolveRelationship(metaReader, metaReader.getLinkedTables,
+		dbHandler, hubName, hubRevision)
+}
+
+func (metaReader *MetaReader) getLinkedTables(dbHandler rdbmstool.DbHandlerProxy, tableName string) ([]string, error) {
+	return getLinkedFK(dbHandler, metaReader.SchemaName, tableName)
+}
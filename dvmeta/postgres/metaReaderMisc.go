@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/rdbmstool"
+)
+
+//getTableName to get list of datatables' name which match with provided keyword (backslash
+//is escape character); name which is not valid data vault table name is reported as *dvmeta.ReadError
+func getTableName(db rdbmstool.DbHandlerProxy, schemaName string, keyword string) ([]dvmeta.EntityInfo, error) {
+	rows, queryErr := db.Query("SELECT table_name FROM information_schema.tables "+
+		"WHERE table_schema = $1 AND table_name LIKE $2 ORDER BY table_name", schemaName, keyword)
+	if queryErr != nil {
+		return nil, fmt.Errorf("DV PostgreSQL meta reader fail to query data table from database: %s",
+			queryErr.Error())
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if scanErr := rows.Scan(&table); scanErr != nil {
+			return nil, scanErr
+		}
+
+		tables = append(tables, table)
+	}
+	if rowErr := rows.Err(); rowErr != nil {
+		return nil, rowErr
+	}
+
+	return dvmeta.ParseEntityInfos(tables)
+}
+
+//getTableDefinition read data table's columns, primary key and foreign keys from information schema
+func getTableDefinition(db rdbmstool.DbHandlerProxy, schemaName string, tableName string) (
+	*rdbmstool.TableDefinition, error) {
+
+	tableDef := rdbmstool.TableDefinition{
+		Name:        tableName,
+		Columns:     []rdbmstool.ColumnDefinition{},
+		PrimaryKey:  []string{},
+		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
+		Indices:     []rdbmstool.IndexKeyDefinition{}}
+
+	//columns
+	rows, queryErr := db.Query("SELECT column_name, data_type, is_nullable, "+
+		"COALESCE(character_maximum_length, numeric_precision, 0), COALESCE(numeric_scale, 0) "+
+		"FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 "+
+		"ORDER BY ordinal_position", schemaName, tableName)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var colName, dataType, isNullable string
+		var length, precision int
+		if scanErr := rows.Scan(&colName, &dataType, &isNullable, &length, &precision); scanErr != nil {
+			return nil, scanErr
+		}
+
+		colDataType, typeErr := parseDataType(dataType)
+		if typeErr != nil {
+			return nil, fmt.Errorf("Column %s of data table %s: %s", colName, tableName, typeErr.Error())
+		}
+
+		col := rdbmstool.ColumnDefinition{
+			Name:       colName,
+			DataType:   colDataType,
+			IsNullable: strings.Compare(isNullable, "YES") == 0}
+		switch colDataType {
+		case rdbmstool.CHAR, rdbmstool.VARCHAR:
+			col.Length = length
+		case rdbmstool.DECIMAL:
+			col.Length = length
+			col.DecimalPrecision = precision
+		}
+
+		tableDef.Columns = append(tableDef.Columns, col)
+	}
+	if rowErr := rows.Err(); rowErr != nil {
+		return nil, rowErr
+	}
+
+	if len(tableDef.Columns) == 0 {
+		return nil, fmt.Errorf("Data table %s not found in schema %s", tableName, schemaName)
+	}
+
+	//primary key
+	pkRows, pkErr := db.Query("SELECT kcu.column_name "+
+		"FROM information_schema.table_constraints tc "+
+		"JOIN information_schema.key_column_usage kcu "+
+		"ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema "+
+		"WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = $1 AND tc.table_name = $2 "+
+		"ORDER BY kcu.ordinal_position", schemaName, tableName)
+	if pkErr != nil {
+		return nil, pkErr
+	}
+	defer pkRows.Close()
+
+	for pkRows.Next() {
+		var colName string
+		if scanErr := pkRows.Scan(&colName); scanErr != nil {
+			return nil, scanErr
+		}
+
+		tableDef.PrimaryKey = append(tableDef.PrimaryKey, colName)
+	}
+	if rowErr := pkRows.Err(); rowErr != nil {
+		return nil, rowErr
+	}
+
+	//foreign keys
+	fkRows, fkErr := db.Query("SELECT tc.constraint_name, kcu.column_name, ccu.table_name, ccu.column_name "+
+		"FROM information_schema.table_constraints tc "+
+		"JOIN information_schema.key_column_usage kcu "+
+		"ON kcu.constraint_name = tc.constraint_name AND kcu.table_schema = tc.table_schema "+
+		"JOIN information_schema.constraint_column_usage ccu "+
+		"ON ccu.constraint_name = tc.constraint_name AND ccu.constraint_schema = tc.table_schema "+
+		"WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = $1 AND tc.table_name = $2 "+
+		"ORDER BY tc.constraint_name, kcu.ordinal_position", schemaName, tableName)
+	if fkErr != nil {
+		return nil, fkErr
+	}
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var fkName, colName, refTable, refColName string
+		if scanErr := fkRows.Scan(&fkName, &colName, &refTable, &refColName); scanErr != nil {
+			return nil, scanErr
+		}
+
+		fkCol := rdbmstool.FKColumnDefinition{ColumnName: colName, RefColumnName: refColName}
+		count := len(tableDef.ForiegnKeys)
+		if count > 0 && strings.Compare(tableDef.ForiegnKeys[count-1].Name, fkName) == 0 {
+			tableDef.ForiegnKeys[count-1].Columns = append(tableDef.ForiegnKeys[count-1].Columns, fkCol)
+		} else {
+			tableDef.ForiegnKeys = append(tableDef.ForiegnKeys, rdbmstool.ForeignKeyDefinition{
+				Name:               fkName,
+				ReferenceTableName: refTable,
+				Columns:            []rdbmstool.FKColumnDefinition{fkCol}})
+		}
+	}
+
+	return &tableDef, fkRows.Err()
+}
+
+//getLinkedFK list data table name(s) which has foreign key refer to given data table
+func getLinkedFK(db rdbmstool.DbHandlerProxy, schemaName string, tableName string) ([]string, error) {
+	rows, queryErr := db.Query("SELECT DISTINCT tc.table_name "+
+		"FROM information_schema.table_constraints tc "+
+		"JOIN information_schema.constraint_column_usage ccu "+
+		"ON ccu.constraint_name = tc.constraint_name AND ccu.constraint_schema = tc.table_schema "+
+		"WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = $1 AND ccu.table_name = $2 "+
+		"ORDER BY tc.table_name", schemaName, tableName)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var table string
+		if scanErr := rows.Scan(&table); scanErr != nil {
+			return nil, scanErr
+		}
+
+		result = append(result, table)
+	}
+
+	return result, rows.Err()
+}
+
+func parseDataType(dataType string) (rdbmstool.ColumnDataType, error) {
+	switch dataType {
+	case "character":
+		return rdbmstool.CHAR, nil
+	case "character varying":
+		return rdbmstool.VARCHAR, nil
+	case "text":
+		return rdbmstool.TEXT, nil
+	case "smallint", "integer", "bigint":
+		return rdbmstool.INTEGER, nil
+	case "numeric":
+		return rdbmstool.DECIMAL, nil
+	case "real", "double precision":
+		return rdbmstool.FLOAT, nil
+	case "date":
+		return rdbmstool.DATE, nil
+	case "timestamp without time zone", "timestamp with time zone":
+		return rdbmstool.DATETIME, nil
+	case "boolean":
+		return rdbmstool.BOOLEAN, nil
+	default:
+		return 0, fmt.Errorf("Unsupported PostgreSQL datatype: %s", dataType)
+	}
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/dialect"
+
+	//explicitly include GO postgres library
+	_ "github.com/lib/pq"
+)
+
+func TestGetHubDefinition(t *testing.T) {
+	db, err := sql.Open(dialect.POSTGRES.DriverName(),
+		dialect.POSTGRES.DataSourceName("localhost", "postgres", "", "test", 5432))
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	transaction, txErr := db.Begin()
+	if txErr != nil {
+		t.Error(txErr.Error())
+		return
+	}
+
+	metaReader := MetaReader{
+		SchemaName: "public"}
+
+	hubDef, err := metaReader.GetHubDefinition("Invoice", 0, transaction)
+	if err != nil {
+		t.Error(err.Error())
+		transaction.Rollback()
+		return
+	}
+
+	if strings.Compare(hubDef.Name, "Invoice") != 0 {
+		t.Errorf("Expect hub name is %s, given %s instead", "Invoice", hubDef.Name)
+	}
+
+	transaction.Rollback()
+}
+
+func TestGetRelationship(t *testing.T) {
+	db, err := sql.Open(dialect.POSTGRES.DriverName(),
+		dialect.POSTGRES.DataSourceName("localhost", "postgres", "", "test", 5432))
+
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	transaction, txErr := db.Begin()
+	if txErr != nil {
+		t.Error(txErr.Error())
+		return
+	}
+
+	metaReader := MetaReader{
+		SchemaName: "public"}
+
+	hubDescriptor, err := metaReader.GetRelationship(transaction, "Invoice", 0)
+	if err != nil {
+		t.Error(err.Error())
+		transaction.Rollback()
+		return
+	}
+
+	if hubDescriptor == nil {
+		t.Error("return value should not be NULL")
+	}
+
+	transaction.Rollback()
+}
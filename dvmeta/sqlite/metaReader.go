@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/rdbmstool"
+)
+
+//MetaReader implementation of DataVaultMetaReader for SQLite database file
+type MetaReader struct {
+}
+
+//GetHubDefinition to get hub metainfo based on hub name and its revision number in transaction mode
+//*must provide database transaction handler
+//example hub name: TaxInvoice, revision: 0
+func (metaReader *MetaReader) GetHubDefinition(
+	hubName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.HubDefinition, error) {
+
+	hubDbName := definition.HubTableName(hubName, revision)
+
+	tableDef, defErr := getTableDefinition(dbHandler, hubDbName)
+	if defErr != nil {
+		return nil, defErr
+	}
+
+	return dvmeta.ParseHubDefinition(hubName, revision, tableDef)
+}
+
+//GetLinkDefinition to get link metainfo based on link name and its revision number
+func (metaReader *MetaReader) GetLinkDefinition(
+	linkName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.LinkDefinition, error) {
+
+	linkDbName := definition.LinkTableName(linkName, revision)
+
+	//read all FK records
+	tableDef, tableErr := getTableDefinition(dbHandler, linkDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseLinkDefinition(linkName, revision, tableDef)
+}
+
+//GetSateliteDefinition get satelite metainfo based on satelite name and its revision number
+func (metaReader *MetaReader) GetSateliteDefinition(
+	satName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.SateliteDefinition, error) {
+
+	satDbName := definition.SateliteTableName(satName, revision)
+
+	//read all FK records
+	tableDef, tableErr := getTableDefinition(dbHandler, satDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseSateliteDefinition(satName, revision, tableDef)
+}
+
+//GetEffectivitySateliteDefinition get effectivity satelite metainfo based on its name and revision number
+func (metaReader *MetaReader) GetEffectivitySateliteDefinition(
+	satName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.EffectivitySateliteDefinition, error) {
+
+	satDbName := definition.EffectivitySateliteTableName(satName, revision)
+
+	tableDef, tableErr := getTableDefinition(dbHandler, satDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseEffectivitySateliteDefinition(satName, revision, tableDef)
+}
+
+//GetReferenceDefinition get reference table metainfo based on reference name and its revision number
+func (metaReader *MetaReader) GetReferenceDefinition(
+	refName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.ReferenceDefinition, error) {
+
+	refDbName := definition.ReferenceTableName(refName, revision)
+
+	tableDef, tableErr := getTableDefinition(dbHandler, refDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseReferenceDefinition(refName, revision, tableDef)
+}
+
+//GetNonHistorizedLinkDefinition get non-historized link metainfo based on link name and its revision number
+func (metaReader *MetaReader) GetNonHistorizedLinkDefinition(
+	linkName string, revision int, dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.NonHistorizedLinkDefinition, error) {
+
+	linkDbName := definition.NonHistorizedLinkTableName(linkName, revision)
+
+	tableDef, tableErr := getTableDefinition(dbHandler, linkDbName)
+	if tableErr != nil {
+		return nil, tableErr
+	}
+
+	return dvmeta.ParseNonHistorizedLinkDefinition(linkName, revision, tableDef)
+}
+
+//GetAllHubs list all available hub(s) entity in given database schema
+func (metaReader *MetaReader) GetAllHubs(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error) {
+	return getTableName(dbHandler, "hub\\_%")
+}
+
+//GetAllLinks list all available link(s) entity in given database schema
+func (metaReader *MetaReader) GetAllLinks(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error) {
+	return getTableName(dbHandler, "link\\_%")
+}
+
+//GetAllSatelites list all available satelite(s) entity in given database schema
+func (metaReader *MetaReader) GetAllSatelites(dbHandler rdbmstool.DbHandlerProxy) ([]dvmeta.EntityInfo, error) {
+	return getTableName(dbHandler, "sat\\_%")
+}
+
+//SearchEntities list all available data vault entities based on given keyword;
+//data table which is not data vault entity is skipped
+func (metaReader *MetaReader) SearchEntities(dbHandler rdbmstool.DbHandlerProxy, searchKeyword string) []dvmeta.EntityInfo {
+	x, err := getTableName(dbHandler, "%"+searchKeyword+"%")
+
+	if _, isReadErr := err.(*dvmeta.ReadError); err != nil && !isReadErr {
+		return []dvmeta.EntityInfo{}
+	}
+
+	return x
+}
+
+//GetDataVaultDefinition read all hubs, links and satelites (all revisions) into data vault definition
+func (metaReader *MetaReader) GetDataVaultDefinition(dbHandler rdbmstool.DbHandlerProxy) (
+	*definition.DataVaultDefinition, error) {
+	return dvmeta.ReadDataVault(metaReader, dbHandler)
+}
+
+//GetRelationship search all direct related links and satelites for provided hub
+func (metaReader *MetaReader) GetRelationship(dbHandler rdbmstool.DbHandlerProxy, hubName string, hubRevision int) (*dvmeta.HubRelationship, error) {
+	return dvmeta.ResolveRelationship(metaReader, metaReader.getLinkedTables,
+		dbHandler, hubName, hubRevision)
+}
+
+func (metaReader *MetaReader) getLinkedTables(dbHandler rdbmstool.DbHandlerProxy, tableName string) ([]string, error) {
+	return getLinkedFK(dbHandler, tableName)
+}
@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/rdbmstool"
+)
+
+//declaredTypePattern parse SQLite declared column type, example: DECIMAL(10,2)
+var declaredTypePattern = regexp.MustCompile(`^\s*([A-Za-z ]+?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?\s*$`)
+
+//getTableName to get list of datatables' name which match with provided keyword (backslash
+//is escape character); name which is not valid data vault table name is reported as *dvmeta.ReadError
+func getTableName(db rdbmstool.DbHandlerProxy, keyword string) ([]dvmeta.EntityInfo, error) {
+	rows, queryErr := db.Query("SELECT name FROM sqlite_master "+
+		"WHERE type = 'table' AND name LIKE ? ESCAPE '\\' ORDER BY name", keyword)
+	if queryErr != nil {
+		return nil, fmt.Errorf("DV SQLite meta reader fail to query data table from database: %s",
+			queryErr.Error())
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if scanErr := rows.Scan(&table); scanErr != nil {
+			return nil, scanErr
+		}
+
+		tables = append(tables, table)
+	}
+	if rowErr := rows.Err(); rowErr != nil {
+		return nil, rowErr
+	}
+
+	return dvmeta.ParseEntityInfos(tables)
+}
+
+//getTableDefinition read data table's columns, primary key and foreign keys from table pragma
+func getTableDefinition(db rdbmstool.DbHandlerProxy, tableName string) (
+	*rdbmstool.TableDefinition, error) {
+
+	tableDef := rdbmstool.TableDefinition{
+		Name:        tableName,
+		Columns:     []rdbmstool.ColumnDefinition{},
+		PrimaryKey:  []string{},
+		UniqueKeys:  []rdbmstool.UniqueKeyDefinition{},
+		ForiegnKeys: []rdbmstool.ForeignKeyDefinition{},
+		Indices:     []rdbmstool.IndexKeyDefinition{}}
+
+	//columns
+	rows, queryErr := db.Query("SELECT name, type, \"notnull\", pk FROM pragma_table_info(?) "+
+		"ORDER BY cid", tableName)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	pks := make(map[int]string)
+	for rows.Next() {
+		var colName, declaredType string
+		var notNull, pk int
+		if scanErr := rows.Scan(&colName, &declaredType, &notNull, &pk); scanErr != nil {
+			return nil, scanErr
+		}
+
+		col, typeErr := parseDeclaredType(declaredType)
+		if typeErr != nil {
+			return nil, fmt.Errorf("Column %s of data table %s: %s", colName, tableName, typeErr.Error())
+		}
+		col.Name = colName
+		col.IsNullable = notNull == 0
+
+		tableDef.Columns = append(tableDef.Columns, col)
+
+		if pk > 0 {
+			pks[pk] = colName
+		}
+	}
+	if rowErr := rows.Err(); rowErr != nil {
+		return nil, rowErr
+	}
+
+	if len(tableDef.Columns) == 0 {
+		return nil, fmt.Errorf("Data table %s not found in database", tableName)
+	}
+
+	for index := 1; index <= len(pks); index++ {
+		tableDef.PrimaryKey = append(tableDef.PrimaryKey, pks[index])
+	}
+
+	//foreign keys
+	fkRows, fkErr := db.Query("SELECT id, \"table\", \"from\", \"to\" FROM pragma_foreign_key_list(?) "+
+		"ORDER BY id, seq", tableName)
+	if fkErr != nil {
+		return nil, fkErr
+	}
+	defer fkRows.Close()
+
+	lastID := -1
+	for fkRows.Next() {
+		var id int
+		var refTable, colName, refColName string
+		if scanErr := fkRows.Scan(&id, &refTable, &colName, &refColName); scanErr != nil {
+			return nil, scanErr
+		}
+
+		fkCol := rdbmstool.FKColumnDefinition{ColumnName: colName, RefColumnName: refColName}
+		if id == lastID {
+			count := len(tableDef.ForiegnKeys)
+			tableDef.ForiegnKeys[count-1].Columns = append(tableDef.ForiegnKeys[count-1].Columns, fkCol)
+		} else {
+			tableDef.ForiegnKeys = append(tableDef.ForiegnKeys, rdbmstool.ForeignKeyDefinition{
+				Name:               fmt.Sprintf("%s_fk_%d", tableName, id),
+				ReferenceTableName: refTable,
+				Columns:            []rdbmstool.FKColumnDefinition{fkCol}})
+		}
+		lastID = id
+	}
+
+	return &tableDef, fkRows.Err()
+}
+
+//getLinkedFK list data table name(s) which has foreign key refer to given data table
+func getLinkedFK(db rdbmstool.DbHandlerProxy, tableName string) ([]string, error) {
+	rows, queryErr := db.Query("SELECT DISTINCT m.name FROM sqlite_master AS m "+
+		"JOIN pragma_foreign_key_list(m.name) AS fk "+
+		"WHERE m.type = 'table' AND fk.\"table\" = ? ORDER BY m.name", tableName)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var table string
+		if scanErr := rows.Scan(&table); scanErr != nil {
+			return nil, scanErr
+		}
+
+		result = append(result, table)
+	}
+
+	return result, rows.Err()
+}
+
+//parseDeclaredType convert SQLite declared column type into column definition
+func parseDeclaredType(declaredType string) (rdbmstool.ColumnDefinition, error) {
+	col := rdbmstool.ColumnDefinition{}
+
+	matches := declaredTypePattern.FindStringSubmatch(declaredType)
+	if matches == nil {
+		return col, fmt.Errorf("Unsupported SQLite datatype: %s", declaredType)
+	}
+
+	length := 0
+	precision := 0
+	if matches[2] != "" {
+		length, _ = strconv.Atoi(matches[2])
+	}
+	if matches[3] != "" {
+		precision, _ = strconv.Atoi(matches[3])
+	}
+
+	switch strings.ToUpper(matches[1]) {
+	case "CHAR", "CHARACTER":
+		col.DataType = rdbmstool.CHAR
+		col.Length = length
+	case "VARCHAR", "CHARACTER VARYING":
+		col.DataType = rdbmstool.VARCHAR
+		col.Length = length
+	case "TEXT":
+		col.DataType = rdbmstool.TEXT
+	case "INT", "INTEGER", "BIGINT", "SMALLINT":
+		col.DataType = rdbmstool.INTEGER
+		col.Length = length
+	case "DECIMAL", "NUMERIC":
+		col.DataType = rdbmstool.DECIMAL
+		col.Length = length
+		col.DecimalPrecision = precision
+	case "FLOAT", "REAL", "DOUBLE":
+		col.DataType = rdbmstool.FLOAT
+	case "DATE":
+		col.DataType = rdbmstool.DATE
+	case "DATETIME", "TIMESTAMP":
+		col.DataType = rdbmstool.DATETIME
+	case "BOOLEAN":
+		col.DataType = rdbmstool.BOOLEAN
+	default:
+		return col, fmt.Errorf("Unsupported SQLite datatype: %s", declaredType)
+	}
+
+	return col, nil
+}
@@ -0,0 +1,408 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/datavault/internal/dvtest"
+	"github.com/guinso/rdbmstool"
+)
+
+func createTestDb(t *testing.T) *sql.DB {
+	satDef := definition.SateliteDefinition{
+		Name:         "Invoice",
+		Revision:     0,
+		HubReference: &definition.HubReference{HubName: "Invoice", Revision: 0},
+		HasHashDiff:  true,
+		Attributes: []definition.SateliteAttributeDefinition{
+			definition.SateliteAttributeDefinition{Name: "DateOfIssue", DataType: rdbmstool.DATE},
+			definition.SateliteAttributeDefinition{Name: "Remark", DataType: rdbmstool.TEXT, IsNullable: true},
+			definition.SateliteAttributeDefinition{Name: "Tax", DataType: rdbmstool.DECIMAL,
+				Length: 10, DecimalPrecision: 2}}}
+
+	return dvtest.CreateSQLiteDb(t, definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Invoice", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "InvoiceNo"}}},
+			definition.HubDefinition{Name: "InvoiceOrder", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "OrderNo"}}}},
+		Links: []definition.LinkDefinition{
+			definition.LinkDefinition{
+				Name:     "InvoiceOrderItem",
+				Revision: 0,
+				HubReferences: []definition.HubReference{
+					definition.HubReference{HubName: "Invoice", Revision: 0},
+					definition.HubReference{HubName: "InvoiceOrder", Revision: 0}}}},
+		Satelites: []definition.SateliteDefinition{satDef}})
+}
+
+func TestSQLiteGetDefinition(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	metaReader := MetaReader{}
+
+	hubDef, err := metaReader.GetHubDefinition("Invoice", 0, db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if strings.Compare(hubDef.Name, "Invoice") != 0 || len(hubDef.BusinessKeys) != 1 {
+		t.Errorf("Expect hub Invoice with one business key, given %s with %d instead",
+			hubDef.Name, len(hubDef.BusinessKeys))
+	}
+
+	linkDef, err := metaReader.GetLinkDefinition("InvoiceOrderItem", 0, db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(linkDef.HubReferences) != 2 {
+		t.Errorf("Expect link has 2 hub references, given %d instead", len(linkDef.HubReferences))
+	}
+
+	satDef, err := metaReader.GetSateliteDefinition("Invoice", 0, db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(satDef.Attributes) != 3 || !satDef.HasHashDiff {
+		t.Errorf("Expect satelite has 3 attributes and hash diff, given %d and %t instead",
+			len(satDef.Attributes), satDef.HasHashDiff)
+		return
+	}
+
+	if satDef.Attributes[2].DataType != rdbmstool.DECIMAL || satDef.Attributes[2].DecimalPrecision != 2 {
+		t.Errorf("Expect tax attribute is DECIMAL(10,2), given %s(%d,%d) instead",
+			satDef.Attributes[2].DataType.String(), satDef.Attributes[2].Length,
+			satDef.Attributes[2].DecimalPrecision)
+	}
+}
+
+func TestSQLiteGetAllEntities(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	metaReader := MetaReader{}
+
+	if hubs, err := metaReader.GetAllHubs(db); err != nil || len(hubs) != 2 {
+		t.Errorf("Expect 2 hubs, given %d (%v) instead", len(hubs), err)
+	}
+
+	if links, err := metaReader.GetAllLinks(db); err != nil || len(links) != 1 {
+		t.Errorf("Expect 1 link, given %d (%v) instead", len(links), err)
+	}
+
+	if sats, err := metaReader.GetAllSatelites(db); err != nil || len(sats) != 1 {
+		t.Errorf("Expect 1 satelite, given %d (%v) instead", len(sats), err)
+	}
+
+	relationship, err := metaReader.GetRelationship(db, "Invoice", 0)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(relationship.Satelites) != 1 || len(relationship.Links) != 1 {
+		t.Errorf("Expect 1 satelite and 1 link related to Invoice, given %d and %d instead",
+			len(relationship.Satelites), len(relationship.Links))
+	}
+}
+
+func TestSQLiteGetRoleLinkDefinition(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	hubDef := definition.HubDefinition{Name: "Employee", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "EmployeeNo"}}}
+	linkDef := definition.LinkDefinition{
+		Name:     "EmployeeManager",
+		Revision: 0,
+		HubReferences: []definition.HubReference{
+			definition.HubReference{HubName: "Employee", Revision: 0},
+			definition.HubReference{HubName: "Employee", Revision: 0, Role: "Manager"}}}
+	esatDef := definition.EffectivitySateliteDefinition{
+		Name:          "EmployeeManager",
+		LinkReference: &definition.LinkReference{LinkName: "EmployeeManager"},
+		DrivingKey:    &definition.HubReference{HubName: "Employee", Role: "Manager"}}
+
+	dvtest.CreateSchema(t, db, dialect.SQLITE, definition.DataVaultDefinition{
+		Hubs:  []definition.HubDefinition{hubDef},
+		Links: []definition.LinkDefinition{linkDef}}, esatDef.GenerateDialectSQL)
+
+	metaReader := MetaReader{}
+	readDef, err := metaReader.GetLinkDefinition("EmployeeManager", 0, db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	roles := map[string]bool{}
+	for _, hubRef := range readDef.HubReferences {
+		roles[hubRef.Role] = hubRef.HubName == "Employee"
+	}
+
+	if len(readDef.HubReferences) != 2 || !roles[""] || !roles["Manager"] {
+		t.Errorf("Expect employee and manager role reference, given %v instead", readDef.HubReferences)
+	}
+
+	readEsat, esatErr := metaReader.GetEffectivitySateliteDefinition("EmployeeManager", 0, db)
+	if esatErr != nil {
+		t.Fatal(esatErr.Error())
+	}
+
+	if readEsat.DrivingKey.Role != "Manager" {
+		t.Errorf("Expect manager role driving key, given %v instead", readEsat.DrivingKey)
+	}
+
+	if validateErr := readEsat.Validate(readDef); validateErr != nil {
+		t.Error(validateErr.Error())
+	}
+
+	relationship, relErr := metaReader.GetRelationship(db, "Employee", 0)
+	if relErr != nil {
+		t.Error(relErr.Error())
+		return
+	}
+
+	if len(relationship.Links) != 1 {
+		t.Errorf("Expect hierarchical link is resolved once, given %d instead", len(relationship.Links))
+	}
+}
+
+func TestSQLiteGetTypedHubDefinition(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	hubDef := definition.HubDefinition{Name: "Account", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "AccountNo", DataType: rdbmstool.INTEGER},
+		definition.BusinessKeyDefinition{Name: "Email", DataType: rdbmstool.VARCHAR, Length: 255},
+		definition.BusinessKeyDefinition{Name: "Branch"}}}
+
+	dvtest.CreateSchema(t, db, dialect.SQLITE, definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{hubDef}})
+
+	metaReader := MetaReader{}
+	readDef, err := metaReader.GetHubDefinition("Account", 0, db)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	expected := []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "AccountNo", DataType: rdbmstool.INTEGER},
+		definition.BusinessKeyDefinition{Name: "Email", DataType: rdbmstool.VARCHAR, Length: 255},
+		definition.BusinessKeyDefinition{Name: "Branch", DataType: rdbmstool.CHAR, Length: 100}}
+	if len(readDef.BusinessKeys) != len(expected) {
+		t.Fatalf("Expect %d business keys, given %v instead", len(expected), readDef.BusinessKeys)
+	}
+
+	for index, bk := range expected {
+		if readDef.BusinessKeys[index] != bk {
+			t.Errorf("Expect business key %v, given %v instead", bk, readDef.BusinessKeys[index])
+		}
+	}
+}
+
+func TestSQLiteDiffDataVault(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	invoiceHub := definition.HubDefinition{Name: "Invoice", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "InvoiceNo"}}}
+	orderHub := definition.HubDefinition{Name: "InvoiceOrder", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "OrderNo"}}}
+	employeeHub := definition.HubDefinition{Name: "Employee", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "EmployeeNo"}}}
+	invoiceSat := definition.SateliteDefinition{
+		Name:         "Invoice",
+		Revision:     0,
+		HubReference: &definition.HubReference{HubName: "Invoice", Revision: 0},
+		HasHashDiff:  true,
+		Attributes: []definition.SateliteAttributeDefinition{
+			definition.SateliteAttributeDefinition{Name: "DateOfIssue", DataType: rdbmstool.DATE},
+			definition.SateliteAttributeDefinition{Name: "Remark", DataType: rdbmstool.TEXT, IsNullable: true},
+			definition.SateliteAttributeDefinition{Name: "Tax", DataType: rdbmstool.DECIMAL,
+				Length: 10, DecimalPrecision: 2}}}
+	dvDef := definition.DataVaultDefinition{
+		Hubs:      []definition.HubDefinition{invoiceHub, orderHub, employeeHub},
+		Satelites: []definition.SateliteDefinition{invoiceSat}}
+
+	metaReader := MetaReader{}
+	diff, err := dvmeta.DiffDataVault(&metaReader, db, &dvDef)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(diff.Differences) != 2 ||
+		diff.Differences[0].Type != dvmeta.MISSING_ENTITY || diff.Differences[0].Name != "Employee" ||
+		diff.Differences[1].Type != dvmeta.EXTRA_ENTITY || diff.Differences[1].EntityType != definition.LINK {
+		t.Fatalf("Expect missing employee hub and extra link, given %v instead", diff.Differences)
+	}
+
+	plan, planErr := diff.GeneratePlan(dialect.SQLITE)
+	if planErr != nil {
+		t.Fatal(planErr.Error())
+	}
+
+	if len(plan) != 1 || !strings.Contains(plan[0], "CREATE TABLE \"hub_employee_rev0\"") {
+		t.Fatalf("Expect plan only create employee hub, given %v instead", plan)
+	}
+
+	dvtest.ExecSQL(t, db, plan...)
+
+	dvDef.Satelites[0].Attributes[2].Length = 12
+	dvDef.Satelites[0].Attributes[1] = definition.SateliteAttributeDefinition{
+		Name: "Note", DataType: rdbmstool.TEXT, IsNullable: true}
+	diff, err = dvmeta.DiffDataVault(&metaReader, db, &dvDef)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	found := make(map[dvmeta.DiffType]string)
+	for _, difference := range diff.Differences {
+		found[difference.Type] = difference.Column
+	}
+
+	if found[dvmeta.COLUMN_MISMATCH] != "tax" || found[dvmeta.MISSING_COLUMN] != "note" ||
+		found[dvmeta.EXTRA_COLUMN] != "remark" {
+		t.Errorf("Expect tax mismatch, missing note and extra remark, given %v instead", diff.Differences)
+	}
+
+	if _, mismatchErr := diff.GeneratePlan(dialect.SQLITE); mismatchErr == nil {
+		t.Error("Expect plan is not generated for column mismatch")
+	}
+}
+
+func TestSQLiteDiffUnreadableEntityAndHashLength(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	dvtest.ExecSQL(t, db, "CREATE TABLE hub_broken_rev0 (broken_hash_key CHAR(32) NOT NULL, "+
+		"load_date DATETIME NOT NULL, record_source CHAR(100) NOT NULL, score REAL NOT NULL)")
+
+	dvDef := definition.DataVaultDefinition{
+		HashAlgorithm: hashkey.SHA256,
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Broken", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "Score"}}},
+			definition.HubDefinition{Name: "Invoice", BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "InvoiceNo"}}}}}
+
+	metaReader := MetaReader{}
+	diff, err := dvmeta.DiffDataVault(&metaReader, db, &dvDef)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	found := make(map[dvmeta.DiffType]dvmeta.SchemaDifference)
+	for _, difference := range diff.Differences {
+		found[difference.Type] = difference
+	}
+
+	if unreadable := found[dvmeta.UNREADABLE_ENTITY]; unreadable.Name != "Broken" {
+		t.Errorf("Expect unreadable broken hub, given %v instead", diff.Differences)
+	}
+
+	if mismatch := found[dvmeta.COLUMN_MISMATCH]; mismatch.Name != "Invoice" ||
+		mismatch.Column != "invoice_hash_key" || !strings.Contains(mismatch.Description, "CHAR(32) in database") {
+		t.Errorf("Expect invoice hash key length mismatch, given %v instead", diff.Differences)
+	}
+
+	if _, planErr := diff.GeneratePlan(dialect.SQLITE); planErr == nil {
+		t.Error("Expect plan is not generated for unreadable entity and hash key mismatch")
+	}
+}
+
+func TestSQLiteDiffPlanSateliteOfExistingHub(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	dvDef := definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{
+			definition.HubDefinition{Name: "Invoice", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+				definition.BusinessKeyDefinition{Name: "InvoiceNo"}}}},
+		Satelites: []definition.SateliteDefinition{
+			definition.SateliteDefinition{
+				Name:         "InvoiceDelivery",
+				HubReference: &definition.HubReference{HubName: "Invoice", Revision: 0},
+				Attributes: []definition.SateliteAttributeDefinition{
+					definition.SateliteAttributeDefinition{Name: "Address", DataType: rdbmstool.TEXT}}}}}
+
+	metaReader := MetaReader{}
+	diff, err := dvmeta.DiffDataVault(&metaReader, db, &dvDef)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	plan, planErr := diff.GeneratePlan(dialect.SQLITE)
+	if planErr != nil {
+		t.Fatal(planErr.Error())
+	}
+
+	if len(plan) != 1 || !strings.Contains(plan[0], "CREATE TABLE \"sat_invoice_delivery_rev0\"") {
+		t.Fatalf("Expect plan only create invoice delivery satelite, given %v instead", plan)
+	}
+
+	dvtest.ExecSQL(t, db, plan...)
+}
+
+func TestSQLiteGetDataVaultDefinition(t *testing.T) {
+	db := createTestDb(t)
+	defer db.Close()
+
+	//sat_invoice_backup is not valid data vault table name, hubspot is not data vault table at all
+	dvtest.ExecSQL(t, db, "CREATE TABLE hub_broken_rev0 (broken_hash_key CHAR(32) NOT NULL, "+
+		"load_date DATETIME NOT NULL, record_source CHAR(100) NOT NULL, score REAL NOT NULL)",
+		"CREATE TABLE sat_invoice_backup (note TEXT)",
+		"CREATE TABLE hubspot (note TEXT)")
+
+	metaReader := MetaReader{}
+	dvDef, err := metaReader.GetDataVaultDefinition(db)
+	if dvDef == nil {
+		t.Fatalf("Expect data vault definition is read, given error %v instead", err)
+	}
+
+	readErr, ok := err.(*dvmeta.ReadError)
+	if !ok || len(readErr.Problems) != 2 ||
+		!strings.HasPrefix(readErr.Problems[0], "data table sat_invoice_backup:") ||
+		!strings.Contains(readErr.Problems[1], "hub Broken revision 0") {
+		t.Errorf("Expect invalid satelite table name and broken hub are reported, given %v instead", err)
+	}
+
+	satInfos, listErr := metaReader.GetAllSatelites(db)
+	if _, isReadErr := listErr.(*dvmeta.ReadError); !isReadErr || len(satInfos) != 1 {
+		t.Errorf("Expect 1 satelite listed with listing error, given %v and %v instead", satInfos, listErr)
+	}
+
+	if len(dvDef.Hubs) != 2 || len(dvDef.Links) != 1 || len(dvDef.Satelites) != 1 {
+		t.Errorf("Expect 2 hubs, 1 link and 1 satelite, given %d, %d and %d instead",
+			len(dvDef.Hubs), len(dvDef.Links), len(dvDef.Satelites))
+	}
+
+	if validateErr := dvDef.Validate(); validateErr != nil {
+		t.Errorf("Expect definition read from database is valid: %s", validateErr.Error())
+	}
+
+	diff, diffErr := dvmeta.DiffDataVault(&metaReader, db, dvDef)
+	if diffErr != nil {
+		t.Fatal(diffErr.Error())
+	}
+
+	if len(diff.Differences) != 1 || diff.Differences[0].Type != dvmeta.EXTRA_ENTITY ||
+		diff.Differences[0].Name != "Broken" {
+		t.Errorf("Expect only unreadable hub is extra entity, given %v instead", diff.Differences)
+	}
+
+	if len(diff.Warnings) != 1 || !strings.HasPrefix(diff.Warnings[0], "data table sat_invoice_backup:") {
+		t.Errorf("Expect invalid satelite table name is warned, given %v instead", diff.Warnings)
+	}
+}
@@ -0,0 +1,182 @@
+package dvmodel
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/rdbmstool"
+	"gopkg.in/yaml.v3"
+)
+
+//model file schema; field name must be the same as one read by ParseModel
+type modelFile struct {
+	HashAlgorithm string          `yaml:"hashAlgorithm,omitempty" json:"hashAlgorithm,omitempty"`
+	Hubs          []modelHub      `yaml:"hubs" json:"hubs"`
+	Links         []modelLink     `yaml:"links" json:"links"`
+	Satelites     []modelSatelite `yaml:"satelites" json:"satelites"`
+}
+
+type modelHub struct {
+	Name         string             `yaml:"name" json:"name"`
+	Revision     int                `yaml:"revision" json:"revision"`
+	BusinessKeys []modelBusinessKey `yaml:"businessKeys" json:"businessKeys"`
+}
+
+type modelBusinessKey struct {
+	Name     string `yaml:"name" json:"name"`
+	DataType string `yaml:"dataType,omitempty" json:"dataType,omitempty"`
+	Length   int    `yaml:"length,omitempty" json:"length,omitempty"`
+}
+
+type modelLink struct {
+	Name          string              `yaml:"name" json:"name"`
+	Revision      int                 `yaml:"revision" json:"revision"`
+	HubReferences []modelHubReference `yaml:"hubReferences" json:"hubReferences"`
+}
+
+type modelHubReference struct {
+	HubName  string `yaml:"hubName" json:"hubName"`
+	Revision int    `yaml:"revision" json:"revision"`
+	Role     string `yaml:"role,omitempty" json:"role,omitempty"`
+}
+
+type modelLinkReference struct {
+	LinkName string `yaml:"linkName" json:"linkName"`
+	Revision int    `yaml:"revision" json:"revision"`
+}
+
+type modelSatelite struct {
+	Name            string              `yaml:"name" json:"name"`
+	Revision        int                 `yaml:"revision" json:"revision"`
+	HubReference    *modelHubReference  `yaml:"hubReference,omitempty" json:"hubReference,omitempty"`
+	LinkReference   *modelLinkReference `yaml:"linkReference,omitempty" json:"linkReference,omitempty"`
+	HasHashDiff     bool                `yaml:"hasHashDiff,omitempty" json:"hasHashDiff,omitempty"`
+	MultiActiveKeys []string            `yaml:"multiActiveKeys,omitempty" json:"multiActiveKeys,omitempty"`
+	Attributes      []modelAttribute    `yaml:"attributes" json:"attributes"`
+}
+
+type modelAttribute struct {
+	Name             string `yaml:"name" json:"name"`
+	DataType         string `yaml:"dataType" json:"dataType"`
+	Length           int    `yaml:"length,omitempty" json:"length,omitempty"`
+	DecimalPrecision int    `yaml:"decimalPrecision,omitempty" json:"decimalPrecision,omitempty"`
+	IsNullable       bool   `yaml:"isNullable,omitempty" json:"isNullable,omitempty"`
+}
+
+//ExportYAML convert data vault definition into YAML model file content
+func ExportYAML(dvDef *definition.DataVaultDefinition) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(createModelFile(dvDef)); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+//ExportJSON convert data vault definition into JSON model file content
+func ExportJSON(dvDef *definition.DataVaultDefinition) ([]byte, error) {
+	return json.MarshalIndent(createModelFile(dvDef), "", "  ")
+}
+
+//ExportModelFile write data vault definition into model file;
+//JSON is used if file extension is .json, otherwise YAML
+func ExportModelFile(dvDef *definition.DataVaultDefinition, filePath string) error {
+	var content []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		content, err = ExportJSON(dvDef)
+	} else {
+		content, err = ExportYAML(dvDef)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filePath, content, 0644)
+}
+
+func createModelFile(dvDef *definition.DataVaultDefinition) *modelFile {
+	model := modelFile{
+		Hubs:      []modelHub{},
+		Links:     []modelLink{},
+		Satelites: []modelSatelite{}}
+
+	if dvDef.HashAlgorithm > 0 {
+		model.HashAlgorithm = dvDef.HashAlgorithm.String()
+	}
+
+	for _, hubDef := range dvDef.Hubs {
+		hub := modelHub{Name: hubDef.Name, Revision: hubDef.Revision, BusinessKeys: []modelBusinessKey{}}
+		for _, bk := range hubDef.BusinessKeys {
+			hub.BusinessKeys = append(hub.BusinessKeys, modelBusinessKey{
+				Name:     bk.Name,
+				DataType: formatDataType(bk.DataType),
+				Length:   bk.Length})
+		}
+		model.Hubs = append(model.Hubs, hub)
+	}
+
+	for _, linkDef := range dvDef.Links {
+		link := modelLink{Name: linkDef.Name, Revision: linkDef.Revision, HubReferences: []modelHubReference{}}
+		for _, hubRef := range linkDef.HubReferences {
+			link.HubReferences = append(link.HubReferences, createModelHubReference(&hubRef))
+		}
+		model.Links = append(model.Links, link)
+	}
+
+	for _, satDef := range dvDef.Satelites {
+		sat := modelSatelite{
+			Name:            satDef.Name,
+			Revision:        satDef.Revision,
+			HasHashDiff:     satDef.HasHashDiff,
+			MultiActiveKeys: satDef.MultiActiveKeys,
+			Attributes:      []modelAttribute{}}
+
+		if satDef.HubReference != nil {
+			hubRef := createModelHubReference(satDef.HubReference)
+			sat.HubReference = &hubRef
+		}
+
+		if satDef.LinkReference != nil {
+			sat.LinkReference = &modelLinkReference{
+				LinkName: satDef.LinkReference.LinkName,
+				Revision: satDef.LinkReference.Revision}
+		}
+
+		for _, attr := range satDef.Attributes {
+			sat.Attributes = append(sat.Attributes, modelAttribute{
+				Name:             attr.Name,
+				DataType:         formatDataType(attr.DataType),
+				Length:           attr.Length,
+				DecimalPrecision: attr.DecimalPrecision,
+				IsNullable:       attr.IsNullable})
+		}
+		model.Satelites = append(model.Satelites, sat)
+	}
+
+	return &model
+}
+
+func createModelHubReference(hubRef *definition.HubReference) modelHubReference {
+	return modelHubReference{HubName: hubRef.HubName, Revision: hubRef.Revision, Role: hubRef.Role}
+}
+
+func formatDataType(dataType rdbmstool.ColumnDataType) string {
+	if dataType == 0 {
+		return ""
+	}
+
+	return dataType.String()
+}
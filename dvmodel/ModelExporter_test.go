@@ -0,0 +1,86 @@
+package dvmodel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+)
+
+func TestExportModel(t *testing.T) {
+	dvDef, err := ParseModel([]byte(testModel))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	yamlContent, yamlErr := ExportYAML(dvDef)
+	if yamlErr != nil {
+		t.Fatal(yamlErr.Error())
+	}
+
+	jsonContent, jsonErr := ExportJSON(dvDef)
+	if jsonErr != nil {
+		t.Fatal(jsonErr.Error())
+	}
+
+	for _, content := range [][]byte{yamlContent, jsonContent} {
+		readDef, readErr := ParseModel(content)
+		if readErr != nil {
+			t.Errorf("Fail to parse exported model: %s\n%s", readErr.Error(), string(content))
+			continue
+		}
+
+		if !reflect.DeepEqual(dvDef, readDef) {
+			t.Errorf("Expect exported model is parsed into the same definition:\n%s", string(content))
+		}
+	}
+}
+
+func TestExportModelHashAlgorithm(t *testing.T) {
+	dvDef, err := ParseModel([]byte("hashAlgorithm: sha256\n" + testModel))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if dvDef.HashAlgorithm != hashkey.SHA256 {
+		t.Errorf("Expect SHA-256 hash algorithm, given %s instead", dvDef.HashAlgorithm)
+	}
+
+	sqls, sqlErr := dvDef.GenerateDialectSQL(dialect.SQLITE)
+	if sqlErr != nil {
+		t.Fatal(sqlErr.Error())
+	}
+
+	if !strings.Contains(sqls[0], "\"customer_hash_key\" CHAR(64) NOT NULL") {
+		t.Errorf("Expect hash key column sized for SHA-256:\n%s", sqls[0])
+	}
+
+	yamlContent, yamlErr := ExportYAML(dvDef)
+	if yamlErr != nil {
+		t.Fatal(yamlErr.Error())
+	}
+
+	jsonContent, jsonErr := ExportJSON(dvDef)
+	if jsonErr != nil {
+		t.Fatal(jsonErr.Error())
+	}
+
+	for _, content := range [][]byte{yamlContent, jsonContent} {
+		readDef, readErr := ParseModel(content)
+		if readErr != nil {
+			t.Errorf("Fail to parse exported model: %s\n%s", readErr.Error(), string(content))
+			continue
+		}
+
+		if !reflect.DeepEqual(dvDef, readDef) {
+			t.Errorf("Expect hash algorithm is exported:\n%s", string(content))
+		}
+	}
+
+	if _, unknownErr := ParseModel([]byte("hashAlgorithm: crc32\n" + testModel)); unknownErr == nil ||
+		!strings.Contains(unknownErr.Error(), "line 1: unsupported hash algorithm crc32") {
+		t.Errorf("Expect error for unsupported hash algorithm, given %v instead", unknownErr)
+	}
+}
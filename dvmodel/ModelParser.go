@@ -0,0 +1,501 @@
+package dvmodel
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+	"gopkg.in/yaml.v3"
+)
+
+//ModelError is list of problem(s) found in model file, each prefixed by its line number
+type ModelError struct {
+	Problems []string
+}
+
+func (modelErr *ModelError) Error() string {
+	return fmt.Sprintf("%d model problem(s) found:\n- %s",
+		len(modelErr.Problems), strings.Join(modelErr.Problems, "\n- "))
+}
+
+//modelParser walk through YAML node tree and collect problem found with its line number
+type modelParser struct {
+	modelErr ModelError
+}
+
+//entityNode keep node of parsed entity, so problem found later can be reported with line number
+type entityNode struct {
+	node      *yaml.Node
+	refNodes  []*yaml.Node
+	tableName string
+}
+
+//ParseModelFile read model file (YAML or JSON) into data vault definition
+func ParseModelFile(filePath string) (*definition.DataVaultDefinition, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseModel(content)
+}
+
+//ParseModel parse model content (YAML or JSON) into data vault definition;
+//all problems found are reported at once as *ModelError with line number
+func ParseModel(content []byte) (*definition.DataVaultDefinition, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil, errors.New("model file is empty")
+	}
+
+	parser := modelParser{}
+	dvDef := definition.DataVaultDefinition{
+		Hubs:      []definition.HubDefinition{},
+		Satelites: []definition.SateliteDefinition{},
+		Links:     []definition.LinkDefinition{}}
+	var hubNodes, linkNodes, satNodes []entityNode
+
+	parser.fields(root.Content[0], func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "hashAlgorithm":
+			parser.parseHashAlgorithm(value, &dvDef.HashAlgorithm)
+		case "hubs":
+			parser.items(value, func(item *yaml.Node) {
+				if hubDef, ok := parser.parseHub(item); ok {
+					dvDef.Hubs = append(dvDef.Hubs, *hubDef)
+					hubNodes = append(hubNodes, entityNode{node: item, tableName: hubDef.GetDbTableName()})
+				}
+			})
+		case "links":
+			parser.items(value, func(item *yaml.Node) {
+				if linkDef, refNodes, ok := parser.parseLink(item); ok {
+					dvDef.Links = append(dvDef.Links, *linkDef)
+					linkNodes = append(linkNodes, entityNode{node: item, refNodes: refNodes,
+						tableName: linkDef.GetDbTableName()})
+				}
+			})
+		case "satelites":
+			parser.items(value, func(item *yaml.Node) {
+				if satDef, refNode, ok := parser.parseSatelite(item); ok {
+					dvDef.Satelites = append(dvDef.Satelites, *satDef)
+					satNodes = append(satNodes, entityNode{node: item, refNodes: []*yaml.Node{refNode},
+						tableName: satDef.GetDbTableName()})
+				}
+			})
+		default:
+			parser.add(key, "unknown field %s", key.Value)
+		}
+	})
+
+	parser.checkDefinition(&dvDef, hubNodes, linkNodes, satNodes)
+
+	if len(parser.modelErr.Problems) > 0 {
+		return nil, &parser.modelErr
+	}
+
+	if validateErr := dvDef.Validate(); validateErr != nil {
+		return nil, validateErr
+	}
+
+	return &dvDef, nil
+}
+
+func (parser *modelParser) add(node *yaml.Node, format string, args ...interface{}) {
+	parser.modelErr.Problems = append(parser.modelErr.Problems,
+		fmt.Sprintf("line %d: %s", node.Line, fmt.Sprintf(format, args...)))
+}
+
+//fields iterate each key-value pair of mapping node
+func (parser *modelParser) fields(node *yaml.Node, handler func(key *yaml.Node, value *yaml.Node)) bool {
+	if node.Kind != yaml.MappingNode {
+		parser.add(node, "expect mapping")
+		return false
+	}
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		handler(node.Content[index], node.Content[index+1])
+	}
+
+	return true
+}
+
+//items iterate each item of sequence node
+func (parser *modelParser) items(node *yaml.Node, handler func(item *yaml.Node)) {
+	if node.Kind != yaml.SequenceNode {
+		parser.add(node, "expect list")
+		return
+	}
+
+	for _, item := range node.Content {
+		handler(item)
+	}
+}
+
+func (parser *modelParser) scalar(node *yaml.Node) (string, bool) {
+	if node.Kind != yaml.ScalarNode {
+		parser.add(node, "expect scalar value")
+		return "", false
+	}
+
+	return node.Value, true
+}
+
+func (parser *modelParser) parseString(node *yaml.Node, value *string) {
+	if text, ok := parser.scalar(node); ok {
+		*value = text
+	}
+}
+
+func (parser *modelParser) parseInt(node *yaml.Node, value *int) {
+	if text, ok := parser.scalar(node); ok {
+		number, err := strconv.Atoi(text)
+		if err != nil || number < 0 {
+			parser.add(node, "expect non-negative integer, given %s", text)
+			return
+		}
+
+		*value = number
+	}
+}
+
+func (parser *modelParser) parseBool(node *yaml.Node, value *bool) {
+	if text, ok := parser.scalar(node); ok {
+		flag, err := strconv.ParseBool(text)
+		if err != nil {
+			parser.add(node, "expect true or false, given %s", text)
+			return
+		}
+
+		*value = flag
+	}
+}
+
+func (parser *modelParser) parseStrings(node *yaml.Node, values *[]string) {
+	parser.items(node, func(item *yaml.Node) {
+		if text, ok := parser.scalar(item); ok {
+			*values = append(*values, text)
+		}
+	})
+}
+
+func (parser *modelParser) parseDataType(node *yaml.Node, dataType *rdbmstool.ColumnDataType) {
+	text, ok := parser.scalar(node)
+	if !ok {
+		return
+	}
+
+	for _, candidate := range dataTypes {
+		if strings.EqualFold(candidate.String(), text) {
+			*dataType = candidate
+			return
+		}
+	}
+
+	parser.add(node, "unsupported datatype %s", text)
+}
+
+func (parser *modelParser) parseHashAlgorithm(node *yaml.Node, algorithm *hashkey.Algorithm) {
+	text, ok := parser.scalar(node)
+	if !ok {
+		return
+	}
+
+	for _, candidate := range hashAlgorithms {
+		if strings.EqualFold(candidate.String(), text) {
+			*algorithm = candidate
+			return
+		}
+	}
+
+	parser.add(node, "unsupported hash algorithm %s, expect md5, sha1 or sha256", text)
+}
+
+func (parser *modelParser) requireName(node *yaml.Node, kind string, name string) bool {
+	if name == "" {
+		parser.add(node, "%s must has a name", kind)
+		return false
+	}
+
+	return true
+}
+
+func (parser *modelParser) parseHub(node *yaml.Node) (*definition.HubDefinition, bool) {
+	hubDef := definition.HubDefinition{}
+	if !parser.fields(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "name":
+			parser.parseString(value, &hubDef.Name)
+		case "revision":
+			parser.parseInt(value, &hubDef.Revision)
+		case "businessKeys":
+			parser.items(value, func(item *yaml.Node) {
+				bk := definition.BusinessKeyDefinition{}
+				parser.fields(item, func(bkKey *yaml.Node, bkValue *yaml.Node) {
+					switch bkKey.Value {
+					case "name":
+						parser.parseString(bkValue, &bk.Name)
+					case "dataType":
+						parser.parseDataType(bkValue, &bk.DataType)
+					case "length":
+						parser.parseInt(bkValue, &bk.Length)
+					default:
+						parser.add(bkKey, "unknown business key field %s", bkKey.Value)
+					}
+				})
+
+				if _, colErr := bk.GetBusinessKeyColumn(); colErr != nil {
+					parser.add(item, "%s", colErr.Error())
+					return
+				}
+
+				hubDef.BusinessKeys = append(hubDef.BusinessKeys, bk)
+			})
+		default:
+			parser.add(key, "unknown hub field %s", key.Value)
+		}
+	}) {
+		return nil, false
+	}
+
+	if !parser.requireName(node, "hub", hubDef.Name) {
+		return nil, false
+	}
+
+	return &hubDef, true
+}
+
+func (parser *modelParser) parseHubReference(node *yaml.Node) (*definition.HubReference, bool) {
+	hubRef := definition.HubReference{}
+	if !parser.fields(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "hubName":
+			parser.parseString(value, &hubRef.HubName)
+		case "revision":
+			parser.parseInt(value, &hubRef.Revision)
+		case "role":
+			parser.parseString(value, &hubRef.Role)
+		default:
+			parser.add(key, "unknown hub reference field %s", key.Value)
+		}
+	}) {
+		return nil, false
+	}
+
+	if hubRef.HubName == "" {
+		parser.add(node, "hub reference must has hubName")
+		return nil, false
+	}
+
+	return &hubRef, true
+}
+
+func (parser *modelParser) parseLink(node *yaml.Node) (*definition.LinkDefinition, []*yaml.Node, bool) {
+	linkDef := definition.LinkDefinition{}
+	var refNodes []*yaml.Node
+	if !parser.fields(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "name":
+			parser.parseString(value, &linkDef.Name)
+		case "revision":
+			parser.parseInt(value, &linkDef.Revision)
+		case "hubReferences":
+			parser.items(value, func(item *yaml.Node) {
+				if hubRef, ok := parser.parseHubReference(item); ok {
+					linkDef.HubReferences = append(linkDef.HubReferences, *hubRef)
+					refNodes = append(refNodes, item)
+				}
+			})
+		default:
+			parser.add(key, "unknown link field %s", key.Value)
+		}
+	}) {
+		return nil, nil, false
+	}
+
+	if !parser.requireName(node, "link", linkDef.Name) {
+		return nil, nil, false
+	}
+
+	return &linkDef, refNodes, true
+}
+
+func (parser *modelParser) parseSatelite(node *yaml.Node) (*definition.SateliteDefinition, *yaml.Node, bool) {
+	satDef := definition.SateliteDefinition{}
+	refNode := node
+	if !parser.fields(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "name":
+			parser.parseString(value, &satDef.Name)
+		case "revision":
+			parser.parseInt(value, &satDef.Revision)
+		case "hubReference":
+			if hubRef, ok := parser.parseHubReference(value); ok {
+				satDef.HubReference = hubRef
+				refNode = value
+			}
+		case "linkReference":
+			linkRef := definition.LinkReference{}
+			parser.fields(value, func(refKey *yaml.Node, refValue *yaml.Node) {
+				switch refKey.Value {
+				case "linkName":
+					parser.parseString(refValue, &linkRef.LinkName)
+				case "revision":
+					parser.parseInt(refValue, &linkRef.Revision)
+				default:
+					parser.add(refKey, "unknown link reference field %s", refKey.Value)
+				}
+			})
+
+			if linkRef.LinkName == "" {
+				parser.add(value, "link reference must has linkName")
+				return
+			}
+			satDef.LinkReference = &linkRef
+			refNode = value
+		case "hasHashDiff":
+			parser.parseBool(value, &satDef.HasHashDiff)
+		case "multiActiveKeys":
+			parser.parseStrings(value, &satDef.MultiActiveKeys)
+		case "attributes":
+			parser.items(value, func(item *yaml.Node) {
+				if attr, ok := parser.parseAttribute(item); ok {
+					satDef.Attributes = append(satDef.Attributes, *attr)
+				}
+			})
+		default:
+			parser.add(key, "unknown satelite field %s", key.Value)
+		}
+	}) {
+		return nil, nil, false
+	}
+
+	if !parser.requireName(node, "satelite", satDef.Name) {
+		return nil, nil, false
+	}
+
+	if len(satDef.Attributes) == 0 {
+		parser.add(node, "satelite %s must has atleast one attribute", satDef.Name)
+	}
+
+	for _, key := range satDef.MultiActiveKeys {
+		found := false
+		for _, attr := range satDef.Attributes {
+			if strings.EqualFold(attr.Name, key) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			parser.add(node, "multi-active key %s of satelite %s not found in attributes", key, satDef.Name)
+		}
+	}
+
+	return &satDef, refNode, true
+}
+
+func (parser *modelParser) parseAttribute(node *yaml.Node) (*definition.SateliteAttributeDefinition, bool) {
+	attr := definition.SateliteAttributeDefinition{}
+	if !parser.fields(node, func(key *yaml.Node, value *yaml.Node) {
+		switch key.Value {
+		case "name":
+			parser.parseString(value, &attr.Name)
+		case "dataType":
+			parser.parseDataType(value, &attr.DataType)
+		case "length":
+			parser.parseInt(value, &attr.Length)
+		case "decimalPrecision":
+			parser.parseInt(value, &attr.DecimalPrecision)
+		case "isNullable":
+			parser.parseBool(value, &attr.IsNullable)
+		default:
+			parser.add(key, "unknown attribute field %s", key.Value)
+		}
+	}) {
+		return nil, false
+	}
+
+	if !parser.requireName(node, "attribute", attr.Name) {
+		return nil, false
+	}
+
+	if attr.DataType == 0 {
+		parser.add(node, "attribute %s must has dataType", attr.Name)
+		return nil, false
+	}
+
+	if (attr.DataType == rdbmstool.CHAR || attr.DataType == rdbmstool.VARCHAR ||
+		attr.DataType == rdbmstool.DECIMAL) && attr.Length == 0 {
+		parser.add(node, "attribute %s of %s must has length", attr.Name, attr.DataType.String())
+	}
+
+	return &attr, true
+}
+
+//checkDefinition check entity name is unique per revision and every reference resolves,
+//same as DataVaultDefinition.Validate but with line number
+func (parser *modelParser) checkDefinition(dvDef *definition.DataVaultDefinition,
+	hubNodes []entityNode, linkNodes []entityNode, satNodes []entityNode) {
+
+	hubs := parser.checkUnique("hub", hubNodes)
+	links := parser.checkUnique("link", linkNodes)
+	parser.checkUnique("satelite", satNodes)
+
+	for index, linkDef := range dvDef.Links {
+		if len(linkDef.HubReferences) < 2 {
+			parser.add(linkNodes[index].node, "link %s must has atleast two hub references, given %d",
+				linkDef.Name, len(linkDef.HubReferences))
+		}
+
+		for refIndex, hubRef := range linkDef.HubReferences {
+			if !hubs[hubRef.GetDbTableName()] {
+				parser.add(linkNodes[index].refNodes[refIndex],
+					"link %s refers to hub %s revision %d which is not defined",
+					linkDef.Name, hubRef.HubName, hubRef.Revision)
+			}
+		}
+	}
+
+	for index, satDef := range dvDef.Satelites {
+		refNode := satNodes[index].refNodes[0]
+		if (satDef.HubReference == nil) == (satDef.LinkReference == nil) {
+			parser.add(satNodes[index].node, "satelite %s must refer to either one hub or one link",
+				satDef.Name)
+		} else if satDef.HubReference != nil && !hubs[satDef.HubReference.GetDbTableName()] {
+			parser.add(refNode, "satelite %s refers to hub %s revision %d which is not defined",
+				satDef.Name, satDef.HubReference.HubName, satDef.HubReference.Revision)
+		} else if satDef.LinkReference != nil && !links[satDef.LinkReference.GetDbTableName()] {
+			parser.add(refNode, "satelite %s refers to link %s revision %d which is not defined",
+				satDef.Name, satDef.LinkReference.LinkName, satDef.LinkReference.Revision)
+		}
+	}
+}
+
+func (parser *modelParser) checkUnique(kind string, nodes []entityNode) map[string]bool {
+	tables := make(map[string]bool)
+	for _, entity := range nodes {
+		if tables[entity.tableName] {
+			parser.add(entity.node, "%s %s is defined more than once", kind, entity.tableName)
+		}
+		tables[entity.tableName] = true
+	}
+
+	return tables
+}
+
+//hashAlgorithms is list of supported hash algorithm in model file
+var hashAlgorithms = []hashkey.Algorithm{hashkey.MD5, hashkey.SHA1, hashkey.SHA256}
+
+//dataTypes is list of supported column datatype in model file
+var dataTypes = []rdbmstool.ColumnDataType{
+	rdbmstool.CHAR, rdbmstool.VARCHAR, rdbmstool.TEXT, rdbmstool.INTEGER, rdbmstool.DECIMAL,
+	rdbmstool.FLOAT, rdbmstool.DATE, rdbmstool.DATETIME, rdbmstool.BOOLEAN}
@@ -0,0 +1,108 @@
+package dvmodel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/rdbmstool"
+)
+
+const testModel = `hubs:
+  - name: Customer
+    businessKeys:
+      - name: Email
+        dataType: VARCHAR
+        length: 255
+  - name: Invoice
+    businessKeys:
+      - name: InvoiceNo
+        dataType: INTEGER
+links:
+  - name: InvoiceCustomer
+    hubReferences:
+      - hubName: Invoice
+      - hubName: Customer
+        role: Billing
+satelites:
+  - name: Customer
+    hubReference:
+      hubName: Customer
+    hasHashDiff: true
+    attributes:
+      - name: Remark
+        dataType: TEXT
+        isNullable: true
+  - name: InvoiceCustomer
+    linkReference:
+      linkName: InvoiceCustomer
+    attributes:
+      - name: Amount
+        dataType: DECIMAL
+        length: 10
+        decimalPrecision: 2
+`
+
+func TestParseModel(t *testing.T) {
+	dvDef, err := ParseModel([]byte(testModel))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(dvDef.Hubs) != 2 || len(dvDef.Links) != 1 || len(dvDef.Satelites) != 2 {
+		t.Fatalf("Expect 2 hubs, 1 link and 2 satelites, given %d, %d and %d instead",
+			len(dvDef.Hubs), len(dvDef.Links), len(dvDef.Satelites))
+	}
+
+	if bk := dvDef.Hubs[0].BusinessKeys[0]; bk.DataType != rdbmstool.VARCHAR || bk.Length != 255 {
+		t.Errorf("Expect business key VARCHAR(255), given %v instead", bk)
+	}
+
+	if dvDef.Links[0].HubReferences[1].Role != "Billing" {
+		t.Errorf("Expect billing role reference, given %v instead", dvDef.Links[0].HubReferences)
+	}
+
+	if attr := dvDef.Satelites[1].Attributes[0]; attr.DataType != rdbmstool.DECIMAL ||
+		attr.Length != 10 || attr.DecimalPrecision != 2 {
+		t.Errorf("Expect attribute DECIMAL(10,2), given %v instead", attr)
+	}
+
+	if _, sqlErr := dvDef.GenerateSQL(); sqlErr != nil {
+		t.Error(sqlErr.Error())
+	}
+}
+
+func TestParseModelError(t *testing.T) {
+	_, err := ParseModel([]byte(`hubs:
+  - name: Customer
+    businessKeys:
+      - name: Email
+        dataType: VARCHR
+links:
+  - name: InvoiceCustomer
+    hubReferences:
+      - hubName: Invoice
+satelites:
+  - name: Customer
+    hubReference:
+      hubName: Customer
+    revison: 1
+    attributes:
+      - name: Remark
+`))
+
+	modelErr, ok := err.(*ModelError)
+	if !ok {
+		t.Fatalf("Expect model error, given %v instead", err)
+	}
+
+	for _, expected := range []string{
+		"line 5: unsupported datatype VARCHR",
+		"line 7: link InvoiceCustomer must has atleast two hub references, given 1",
+		"line 9: link InvoiceCustomer refers to hub Invoice revision 0 which is not defined",
+		"line 14: unknown satelite field revison",
+		"line 16: attribute Remark must has dataType"} {
+		if !strings.Contains(modelErr.Error(), expected) {
+			t.Errorf("Expect problem %s, given %s instead", expected, modelErr.Error())
+		}
+	}
+}
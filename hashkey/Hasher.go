@@ -0,0 +1,130 @@
+package hashkey
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"strings"
+)
+
+//Algorithm hash function used to compute data vault hash key
+type Algorithm uint8
+
+//List of supported hash algorithm
+const (
+	MD5 Algorithm = iota + 1
+	SHA1
+	SHA256
+)
+
+const (
+	//DefaultDelimiter is separator placed between normalised values before hashing
+	DefaultDelimiter = ";"
+	//DefaultNullReplacement is substitution for empty (null) value before hashing
+	DefaultNullReplacement = ""
+	//DiffNullMarker is substitution for null attribute value before hash diff is computed;
+	//it never collide with escaped non-null value, so null and empty string differ
+	DiffNullMarker = `\N`
+)
+
+func (algo Algorithm) String() string {
+	if algo == MD5 {
+		return "md5"
+	} else if algo == SHA1 {
+		return "sha1"
+	} else if algo == SHA256 {
+		return "sha256"
+	}
+
+	return "unknown"
+}
+
+//Length is number of hexadecimal characters produced by hash algorithm;
+//definition sizes hash key and hash diff columns by it, so hash key is never truncated
+func (algo Algorithm) Length() int {
+	if algo == SHA1 {
+		return 40
+	} else if algo == SHA256 {
+		return 64
+	}
+
+	return 32
+}
+
+func (algo Algorithm) newHash() hash.Hash {
+	if algo == SHA1 {
+		return sha1.New()
+	} else if algo == SHA256 {
+		return sha256.New()
+	}
+
+	return md5.New()
+}
+
+//Hasher compute data vault hash key by following Data Vault 2.0 normalisation:
+//each value is trimmed, upper-cased, empty value replaced by null replacement,
+//then all values are joined by delimiter before hashed
+type Hasher struct {
+	Algorithm       Algorithm
+	Delimiter       string
+	NullReplacement string
+}
+
+//CreateHasher create hasher instance with default setting (MD5)
+func CreateHasher() *Hasher {
+	return &Hasher{
+		Algorithm:       MD5,
+		Delimiter:       DefaultDelimiter,
+		NullReplacement: DefaultNullReplacement}
+}
+
+//Normalise is to make hash input string from given ordered values
+func (hasher *Hasher) Normalise(values ...string) string {
+	normalised := make([]string, len(values))
+
+	for index, value := range values {
+		tmp := strings.ToUpper(strings.TrimSpace(value))
+		if tmp == "" {
+			tmp = hasher.NullReplacement
+		}
+
+		normalised[index] = tmp
+	}
+
+	return strings.Join(normalised, hasher.Delimiter)
+}
+
+//HashKey is to compute hash key (lower case hexadecimal) from given ordered values
+func (hasher *Hasher) HashKey(values ...string) string {
+	h := hasher.Algorithm.newHash()
+	h.Write([]byte(hasher.Normalise(values...)))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//HashDiff is to compute hash diff (lower case hexadecimal) from given ordered attribute values;
+//unlike hash key, value is not trimmed nor upper-cased so any change is detected; each value is
+//escaped so it never contain delimiter, and nil value is replaced by DiffNullMarker
+func (hasher *Hasher) HashDiff(values ...*string) string {
+	delimiter := hasher.Delimiter
+	if delimiter == "" {
+		delimiter = DefaultDelimiter
+	}
+	escaper := strings.NewReplacer(`\`, `\\`, delimiter, `\`+delimiter)
+
+	escaped := make([]string, len(values))
+	for index, value := range values {
+		if value == nil {
+			escaped[index] = DiffNullMarker
+		} else {
+			escaped[index] = escaper.Replace(*value)
+		}
+	}
+
+	h := hasher.Algorithm.newHash()
+	h.Write([]byte(strings.Join(escaped, delimiter)))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
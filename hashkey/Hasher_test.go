@@ -0,0 +1,65 @@
+package hashkey
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashKeyNormalisation(t *testing.T) {
+	hasher := CreateHasher()
+
+	expected := hasher.HashKey("INV-001", "ACME")
+	if actual := hasher.HashKey("  inv-001 ", "acme"); strings.Compare(expected, actual) != 0 {
+		t.Errorf("Expect normalised hash key %s, given %s instead", expected, actual)
+	}
+
+	if actual := hasher.HashKey("ACME", "INV-001"); strings.Compare(expected, actual) == 0 {
+		t.Error("Hash key should depend on order of values")
+	}
+
+	if len(expected) != MD5.Length() {
+		t.Errorf("Expect hash key length %d, given %d instead", MD5.Length(), len(expected))
+	}
+
+	//md5("INV-001;ACME")
+	if strings.Compare(expected, "f4a7ab80df5c18b6ecbcd75ffbf18159") != 0 {
+		t.Errorf("Expect md5 hash key %s, given %s instead",
+			"f4a7ab80df5c18b6ecbcd75ffbf18159", expected)
+	}
+}
+
+func TestHashKeyAlgorithm(t *testing.T) {
+	for _, algo := range []Algorithm{MD5, SHA1, SHA256} {
+		hasher := CreateHasher()
+		hasher.Algorithm = algo
+
+		if hashKey := hasher.HashKey("INV-001"); len(hashKey) != algo.Length() {
+			t.Errorf("Expect %s hash key length %d, given %d instead",
+				algo.String(), algo.Length(), len(hashKey))
+		}
+	}
+}
+
+func TestHashDiff(t *testing.T) {
+	hasher := CreateHasher()
+	str := func(value string) *string { return &value }
+
+	expected := hasher.HashDiff(str("acme"), str("x "))
+	for _, values := range [][]*string{
+		[]*string{str("ACME"), str("x ")},
+		[]*string{str("acme"), str("x")},
+		[]*string{str("acme;x "), str("")},
+		[]*string{str("acme;x ")}} {
+		if actual := hasher.HashDiff(values...); strings.Compare(expected, actual) == 0 {
+			t.Errorf("Expect different hash diff for %s", *values[0])
+		}
+	}
+
+	if hasher.HashDiff(nil) == hasher.HashDiff(str("")) {
+		t.Error("Expect null and empty string have different hash diff")
+	}
+
+	if hasher.HashDiff(nil) == hasher.HashDiff(str(DiffNullMarker)) {
+		t.Error("Expect null and null marker string have different hash diff")
+	}
+}
@@ -0,0 +1,66 @@
+//Package dvtest is test helper shared by packages' tests to build data vault schema
+package dvtest
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+
+	//explicitly include GO sqlite library
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//Generator is SQL statement generator of entity which is not part of data vault definition,
+//e.g. PIT, bridge, reference table, effectivity satelite or non-historized link
+type Generator func(sqlDialect dialect.Dialect) (string, error)
+
+//CreateSQLiteDb is to open in-memory SQLite database, which enforce foreign key,
+//with data tables of given data vault definition and generators
+func CreateSQLiteDb(t *testing.T, dvDef definition.DataVaultDefinition, generators ...Generator) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	db.SetMaxOpenConns(1)
+
+	CreateSchema(t, db, dialect.SQLITE, dvDef, generators...)
+
+	return db
+}
+
+//CreateSchema is to create data tables of given data vault definition by its GenerateDialectSQL,
+//followed by data tables of generators; test is failed if any statement fail
+func CreateSchema(t *testing.T, db *sql.DB, sqlDialect dialect.Dialect,
+	dvDef definition.DataVaultDefinition, generators ...Generator) {
+	t.Helper()
+
+	sqls, sqlErr := dvDef.GenerateDialectSQL(sqlDialect)
+	if sqlErr != nil {
+		t.Fatal(sqlErr.Error())
+	}
+
+	for _, generate := range generators {
+		sql, genErr := generate(sqlDialect)
+		if genErr != nil {
+			t.Fatal(genErr.Error())
+		}
+		sqls = append(sqls, sql)
+	}
+
+	ExecSQL(t, db, sqls...)
+}
+
+//ExecSQL is to execute SQL statements in order; test is failed if any statement fail
+func ExecSQL(t *testing.T, db *sql.DB, sqls ...string) {
+	t.Helper()
+
+	for _, sql := range sqls {
+		if _, execErr := db.Exec(sql); execErr != nil {
+			t.Fatalf("Fail to execute SQL statement: %s\n%s", execErr.Error(), sql)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//AttributeMapping describe how a column of new revision is filled when data is copied from
+//previous revision; Name is attribute (or business key) name of new revision, Source is
+//attribute name of previous revision, Default is literal value used if Source is empty
+type AttributeMapping struct {
+	Name    string
+	Source  string
+	Default interface{}
+}
+
+//mapAttributeColumns resolve select expression of each target column, by priority:
+//explicit mapping, then source column of same name, then NULL if target column is nullable;
+//isIdentical is true if every target column is copied from source column of same name
+//and no source column is left behind
+func mapAttributeColumns(sqlDialect dialect.Dialect, targets []rdbmstool.ColumnDefinition,
+	sources []rdbmstool.ColumnDefinition, mappings []AttributeMapping, allowDefault bool) (
+	targetNames []string, exprs []string, isIdentical bool, err error) {
+
+	findColumn := func(cols []rdbmstool.ColumnDefinition, name string) *rdbmstool.ColumnDefinition {
+		for index := range cols {
+			if strings.Compare(cols[index].Name, stringtool.ToSnakeCase(name)) == 0 {
+				return &cols[index]
+			}
+		}
+		return nil
+	}
+
+	mapped := make(map[string]AttributeMapping)
+	for _, mapping := range mappings {
+		target := findColumn(targets, mapping.Name)
+		if target == nil {
+			return nil, nil, false, fmt.Errorf("mapping %s not found in new revision", mapping.Name)
+		}
+
+		if _, exists := mapped[target.Name]; exists {
+			return nil, nil, false, fmt.Errorf("mapping %s is defined more than once", mapping.Name)
+		}
+		mapped[target.Name] = mapping
+	}
+
+	isIdentical = len(targets) == len(sources)
+	for _, target := range targets {
+		mapping, hasMapping := mapped[target.Name]
+
+		if !hasMapping && findColumn(sources, target.Name) != nil {
+			targetNames = append(targetNames, target.Name)
+			exprs = append(exprs, sqlDialect.QuoteIdentifier(target.Name))
+			continue
+		}
+
+		isIdentical = false
+
+		if hasMapping && mapping.Source != "" {
+			source := findColumn(sources, mapping.Source)
+			if source == nil {
+				return nil, nil, false, fmt.Errorf("source %s of mapping %s not found in previous revision",
+					mapping.Source, mapping.Name)
+			}
+
+			targetNames = append(targetNames, target.Name)
+			exprs = append(exprs, sqlDialect.QuoteIdentifier(source.Name))
+		} else if hasMapping && mapping.Default != nil {
+			if !allowDefault {
+				return nil, nil, false, fmt.Errorf("mapping %s must has source, default value is not allowed",
+					mapping.Name)
+			}
+
+			literal, literalErr := sqlDialect.RenderLiteral(mapping.Default, target)
+			if literalErr != nil {
+				return nil, nil, false, fmt.Errorf("default value of mapping %s: %s",
+					mapping.Name, literalErr.Error())
+			}
+
+			targetNames = append(targetNames, target.Name)
+			exprs = append(exprs, literal)
+		} else if !target.IsNullable {
+			return nil, nil, false, fmt.Errorf("%s not found in previous revision, "+
+				"mapping with source or default value is required", target.Name)
+		}
+	}
+
+	return targetNames, exprs, isIdentical, nil
+}
+
+//generateCopySQL generate INSERT ... SELECT statement which copy rows from source table
+func generateCopySQL(sqlDialect dialect.Dialect, targetTable string, sourceTable string,
+	targetNames []string, exprs []string) string {
+	return fmt.Sprintf("INSERT INTO %s \n(%s) \nSELECT %s \nFROM %s",
+		sqlDialect.QuoteIdentifier(targetTable), dialect.QuoteIdentifiers(sqlDialect, targetNames),
+		strings.Join(exprs, ", "), sqlDialect.QuoteIdentifier(sourceTable))
+}
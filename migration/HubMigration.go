@@ -0,0 +1,121 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+)
+
+//HubMigration is plan to move hub from previous revision (From) into new revision (To),
+//e.g. change business key datatype or length; Mappings is optional, business key of new
+//revision is copied from business key of same name if no mapping is given;
+//every business key must come from previous revision as hash key is kept as it is
+type HubMigration struct {
+	From     *definition.HubDefinition
+	To       *definition.HubDefinition
+	Mappings []AttributeMapping
+}
+
+//GenerateSQL is to generate SQL statement to create new revision of hub
+func (hubMigration *HubMigration) GenerateSQL() (string, error) {
+	return hubMigration.GenerateDialectSQL(dialect.MYSQL)
+}
+
+//GenerateDialectSQL is to generate SQL statement to create new revision of hub
+//for given SQL dialect
+func (hubMigration *HubMigration) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if err := hubMigration.validate(); err != nil {
+		return "", err
+	}
+
+	return hubMigration.To.GenerateDialectSQL(sqlDialect)
+}
+
+//GenerateCopySQL is to generate SQL statement to copy entries of previous revision
+//into new revision
+func (hubMigration *HubMigration) GenerateCopySQL() (string, error) {
+	return hubMigration.GenerateDialectCopySQL(dialect.MYSQL)
+}
+
+//GenerateDialectCopySQL is to generate SQL statement to copy entries of previous revision
+//into new revision for given SQL dialect
+func (hubMigration *HubMigration) GenerateDialectCopySQL(sqlDialect dialect.Dialect) (string, error) {
+	if err := hubMigration.validate(); err != nil {
+		return "", err
+	}
+
+	targets, targetErr := getBusinessKeyColumns(hubMigration.To.BusinessKeys)
+	if targetErr != nil {
+		return "", targetErr
+	}
+
+	sources, sourceErr := getBusinessKeyColumns(hubMigration.From.BusinessKeys)
+	if sourceErr != nil {
+		return "", sourceErr
+	}
+
+	targetNames, exprs, _, mapErr := mapAttributeColumns(sqlDialect, targets, sources,
+		hubMigration.Mappings, false)
+	if mapErr != nil {
+		return "", fmt.Errorf("Hub %s: %s", hubMigration.To.Name, mapErr.Error())
+	}
+
+	//hash key is computed from business key values, so each business key
+	//of previous revision must be copied exactly once
+	used := make(map[string]bool)
+	for _, expr := range exprs {
+		used[expr] = true
+	}
+	if len(exprs) != len(sources) || len(used) != len(sources) {
+		return "", fmt.Errorf("Hub %s: each business key of previous revision "+
+			"must be copied exactly once to keep hash key", hubMigration.To.Name)
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	cols := []string{
+		hubMigration.To.GetHashKey(),
+		definition.LOAD_DATE,
+		definition.RECORD_SOURCE}
+	values := make([]string, len(cols))
+	for index, col := range cols {
+		values[index] = quote(col)
+	}
+
+	return generateCopySQL(sqlDialect, hubMigration.To.GetDbTableName(),
+		hubMigration.From.GetDbTableName(),
+		append(cols, targetNames...), append(values, exprs...)), nil
+}
+
+func (hubMigration *HubMigration) validate() error {
+	if hubMigration.From == nil || hubMigration.To == nil {
+		return errors.New("Hub migration must has previous and new revision")
+	}
+
+	if strings.Compare(hubMigration.From.GetDbTableName(), hubMigration.To.GetDbTableName()) == 0 {
+		return fmt.Errorf("Hub migration of %s must move into different revision", hubMigration.To.Name)
+	}
+
+	if strings.Compare(hubMigration.From.GetHashKey(), hubMigration.To.GetHashKey()) != 0 {
+		return fmt.Errorf("Hub migration of %s must keep the same hub name", hubMigration.To.Name)
+	}
+
+	return nil
+}
+
+func getBusinessKeyColumns(businessKeys []definition.BusinessKeyDefinition) ([]rdbmstool.ColumnDefinition, error) {
+	cols := make([]rdbmstool.ColumnDefinition, len(businessKeys))
+	for index := range businessKeys {
+		col, err := businessKeys[index].GetBusinessKeyColumn()
+		if err != nil {
+			return nil, err
+		}
+
+		cols[index] = col
+	}
+
+	return cols, nil
+}
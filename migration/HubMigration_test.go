@@ -0,0 +1,59 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/internal/dvtest"
+	"github.com/guinso/rdbmstool"
+)
+
+func TestHubMigration(t *testing.T) {
+	fromDef := definition.HubDefinition{Name: "Customer", Revision: 0, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "Name"}}}
+	toDef := definition.HubDefinition{Name: "Customer", Revision: 1, BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "Email", DataType: rdbmstool.VARCHAR, Length: 255}}}
+	hubMigration := HubMigration{
+		From:     &fromDef,
+		To:       &toDef,
+		Mappings: []AttributeMapping{AttributeMapping{Name: "Email", Source: "Name"}}}
+
+	db := dvtest.CreateSQLiteDb(t, definition.DataVaultDefinition{
+		Hubs: []definition.HubDefinition{fromDef}})
+	defer db.Close()
+	dvtest.ExecSQL(t, db,
+		"INSERT INTO hub_customer_rev0 VALUES ('abc', '2017-08-01 00:00:00', 'test', 'ali@example.com')")
+
+	toSQL, toErr := hubMigration.GenerateDialectSQL(dialect.SQLITE)
+	if toErr != nil {
+		t.Fatal(toErr.Error())
+	}
+
+	if !strings.Contains(toSQL, "\"email\" VARCHAR(255) NOT NULL") {
+		t.Errorf("Expect typed business key in SQL statement: %s", toSQL)
+	}
+
+	copySQL, copyErr := hubMigration.GenerateDialectCopySQL(dialect.SQLITE)
+	if copyErr != nil {
+		t.Fatal(copyErr.Error())
+	}
+
+	dvtest.ExecSQL(t, db, toSQL, copySQL)
+
+	var hashKey, email string
+	if err := db.QueryRow("SELECT customer_hash_key, email FROM hub_customer_rev1").Scan(
+		&hashKey, &email); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if hashKey != "abc" || email != "ali@example.com" {
+		t.Errorf("Expect hub entry is copied, given %s and %s instead", hashKey, email)
+	}
+
+	hubMigration.Mappings = []AttributeMapping{AttributeMapping{Name: "Email", Default: "unknown"}}
+	if _, defaultErr := hubMigration.GenerateDialectCopySQL(dialect.SQLITE); defaultErr == nil {
+		t.Error("Expect error for business key filled by default value")
+	}
+}
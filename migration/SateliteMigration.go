@@ -0,0 +1,110 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+)
+
+//SateliteMigration is plan to move satelite from previous revision (From) into new
+//revision (To), e.g. add or change attributes; Mappings is optional, attribute of new revision
+//is copied from attribute of same name if no mapping is given;
+//hash diff is only copied if both revisions have hash diff and identical attributes,
+//otherwise it is left blank so next load of each parent record a new row
+type SateliteMigration struct {
+	From     *definition.SateliteDefinition
+	To       *definition.SateliteDefinition
+	Mappings []AttributeMapping
+}
+
+//GenerateSQL is to generate SQL statement to create new revision of satelite
+func (satMigration *SateliteMigration) GenerateSQL() (string, error) {
+	return satMigration.GenerateDialectSQL(dialect.MYSQL)
+}
+
+//GenerateDialectSQL is to generate SQL statement to create new revision of satelite
+//for given SQL dialect
+func (satMigration *SateliteMigration) GenerateDialectSQL(sqlDialect dialect.Dialect) (string, error) {
+	if err := satMigration.validate(); err != nil {
+		return "", err
+	}
+
+	return satMigration.To.GenerateDialectSQL(sqlDialect)
+}
+
+//GenerateCopySQL is to generate SQL statement to copy history of previous revision
+//into new revision
+func (satMigration *SateliteMigration) GenerateCopySQL() (string, error) {
+	return satMigration.GenerateDialectCopySQL(dialect.MYSQL)
+}
+
+//GenerateDialectCopySQL is to generate SQL statement to copy history of previous revision
+//into new revision for given SQL dialect
+func (satMigration *SateliteMigration) GenerateDialectCopySQL(sqlDialect dialect.Dialect) (string, error) {
+	if err := satMigration.validate(); err != nil {
+		return "", err
+	}
+
+	targetNames, exprs, isIdentical, mapErr := mapAttributeColumns(sqlDialect,
+		getAttributeColumns(satMigration.To.Attributes),
+		getAttributeColumns(satMigration.From.Attributes),
+		satMigration.Mappings, true)
+	if mapErr != nil {
+		return "", fmt.Errorf("Satelite %s: %s", satMigration.To.Name, mapErr.Error())
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	cols := []string{
+		satMigration.To.GetParentHashKey(),
+		definition.LOAD_DATE,
+		definition.END_DATE,
+		definition.RECORD_SOURCE}
+	values := make([]string, len(cols))
+	for index, col := range cols {
+		values[index] = quote(col)
+	}
+
+	if satMigration.To.HasHashDiff {
+		cols = append(cols, definition.HASH_DIFF)
+		if satMigration.From.HasHashDiff && isIdentical {
+			values = append(values, quote(definition.HASH_DIFF))
+		} else {
+			values = append(values, "''")
+		}
+	}
+
+	return generateCopySQL(sqlDialect, satMigration.To.GetDbTableName(),
+		satMigration.From.GetDbTableName(),
+		append(cols, targetNames...), append(values, exprs...)), nil
+}
+
+func (satMigration *SateliteMigration) validate() error {
+	if satMigration.From == nil || satMigration.To == nil {
+		return errors.New("Satelite migration must has previous and new revision")
+	}
+
+	if strings.Compare(satMigration.From.GetDbTableName(), satMigration.To.GetDbTableName()) == 0 {
+		return fmt.Errorf("Satelite migration of %s must move into different revision",
+			satMigration.To.Name)
+	}
+
+	if satMigration.From.GetParentHashKey() == "" ||
+		strings.Compare(satMigration.From.GetParentHashKey(), satMigration.To.GetParentHashKey()) != 0 {
+		return fmt.Errorf("Satelite migration of %s must keep the same hub or link", satMigration.To.Name)
+	}
+
+	return nil
+}
+
+func getAttributeColumns(attributes []definition.SateliteAttributeDefinition) []rdbmstool.ColumnDefinition {
+	cols := make([]rdbmstool.ColumnDefinition, len(attributes))
+	for index := range attributes {
+		cols[index] = attributes[index].GetAttributeColumn()
+	}
+
+	return cols
+}
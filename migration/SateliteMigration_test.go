@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/internal/dvtest"
+	"github.com/guinso/rdbmstool"
+)
+
+func TestSateliteMigration(t *testing.T) {
+	hubDef := definition.HubDefinition{Name: "Customer", BusinessKeys: []definition.BusinessKeyDefinition{
+		definition.BusinessKeyDefinition{Name: "Name"}}}
+	fromDef := definition.SateliteDefinition{
+		Name:         "Customer",
+		Revision:     0,
+		HubReference: &definition.HubReference{HubName: "Customer"},
+		HasHashDiff:  true,
+		Attributes: []definition.SateliteAttributeDefinition{
+			definition.SateliteAttributeDefinition{Name: "Remark", DataType: rdbmstool.VARCHAR, Length: 50}}}
+	toDef := definition.SateliteDefinition{
+		Name:         "Customer",
+		Revision:     1,
+		HubReference: &definition.HubReference{HubName: "Customer"},
+		HasHashDiff:  true,
+		Attributes: []definition.SateliteAttributeDefinition{
+			definition.SateliteAttributeDefinition{Name: "Note", DataType: rdbmstool.VARCHAR, Length: 200},
+			definition.SateliteAttributeDefinition{Name: "Tier", DataType: rdbmstool.INTEGER},
+			definition.SateliteAttributeDefinition{Name: "Email", DataType: rdbmstool.VARCHAR,
+				Length: 100, IsNullable: true}}}
+	satMigration := SateliteMigration{
+		From: &fromDef,
+		To:   &toDef,
+		Mappings: []AttributeMapping{
+			AttributeMapping{Name: "Note", Source: "Remark"},
+			AttributeMapping{Name: "Tier", Default: 1}}}
+
+	db := dvtest.CreateSQLiteDb(t, definition.DataVaultDefinition{
+		Hubs:      []definition.HubDefinition{hubDef},
+		Satelites: []definition.SateliteDefinition{fromDef}})
+	defer db.Close()
+	dvtest.ExecSQL(t, db,
+		"INSERT INTO hub_customer_rev0 VALUES ('abc', '2017-08-01 00:00:00', 'test', 'Ali')",
+		"INSERT INTO sat_customer_rev0 VALUES ('abc', '2017-08-01 00:00:00', '2017-08-02 00:00:00', 'test', 'h1', 'first')",
+		"INSERT INTO sat_customer_rev0 VALUES ('abc', '2017-08-02 00:00:00', NULL, 'test', 'h2', 'second')")
+
+	toSQL, toErr := satMigration.GenerateDialectSQL(dialect.SQLITE)
+	if toErr != nil {
+		t.Fatal(toErr.Error())
+	}
+
+	copySQL, copyErr := satMigration.GenerateDialectCopySQL(dialect.SQLITE)
+	if copyErr != nil {
+		t.Fatal(copyErr.Error())
+	}
+
+	if !strings.Contains(copySQL, "SELECT \"customer_hash_key\", \"load_date\", \"end_date\", "+
+		"\"record_source\", '', \"remark\", 1 \nFROM \"sat_customer_rev0\"") {
+		t.Errorf("Unexpected copy SQL statement: %s", copySQL)
+	}
+
+	dvtest.ExecSQL(t, db, toSQL, copySQL)
+
+	var note string
+	var tier int
+	if err := db.QueryRow("SELECT note, tier FROM sat_customer_rev1 WHERE end_date IS NULL").Scan(
+		&note, &tier); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if note != "second" || tier != 1 {
+		t.Errorf("Expect current row is copied with note second and tier 1, given %s and %d instead",
+			note, tier)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sat_customer_rev1").Scan(&count); err != nil || count != 2 {
+		t.Errorf("Expect 2 history rows are copied, given %d (%v) instead", count, err)
+	}
+
+	satMigration.Mappings = []AttributeMapping{AttributeMapping{Name: "Note", Source: "Remark"}}
+	if _, missingErr := satMigration.GenerateDialectCopySQL(dialect.SQLITE); missingErr == nil {
+		t.Error("Expect error for non-nullable attribute without source or default value")
+	}
+}
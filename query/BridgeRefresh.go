@@ -0,0 +1,81 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+)
+
+//BridgeRefresh is schema to build bridge rows of a snapshot date
+type BridgeRefresh struct {
+	Definition   *definition.BridgeDefinition
+	SnapshotDate time.Time
+}
+
+//GenerateParamSQL is to generate parameterized SQL statement which insert every path
+//of links loaded before snapshot date; path which already exists in bridge for the
+//snapshot date is skipped, so statement can be run repeatedly to pick up new links
+func (bridgeRefresh *BridgeRefresh) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	bridgeDef := bridgeRefresh.Definition
+	if err := bridgeDef.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+
+	snapshotPlaceholder, placeholderErr := sqlDialect.TypedPlaceholder(rdbmstool.ColumnDefinition{
+		Name: definition.SNAPSHOT_DATE, DataType: rdbmstool.DATETIME})
+	if placeholderErr != nil {
+		return "", nil, placeholderErr
+	}
+
+	columns := []string{definition.SNAPSHOT_DATE, bridgeDef.HubReference.GetHashKey()}
+	values := []string{snapshotPlaceholder,
+		fmt.Sprintf("l0.%s", quote(bridgeDef.HubReference.GetHashKey()))}
+	selectArgs := []interface{}{bridgeRefresh.SnapshotDate}
+
+	var joins []string
+	var joinArgs []interface{}
+	var existConditions []string
+	previousHashKey := bridgeDef.HubReference.GetHashKey()
+	for index, step := range bridgeDef.Path {
+		alias := fmt.Sprintf("l%d", index)
+		linkHashKey := step.LinkReference.GetHashKey()
+		hubHashKey := step.HubReference.GetHashKey()
+
+		columns = append(columns, linkHashKey, hubHashKey)
+		values = append(values,
+			fmt.Sprintf("%s.%s", alias, quote(linkHashKey)),
+			fmt.Sprintf("%s.%s", alias, quote(hubHashKey)))
+		existConditions = append(existConditions,
+			fmt.Sprintf("b.%s = %s.%s", quote(linkHashKey), alias, quote(linkHashKey)))
+
+		if index > 0 {
+			joins = append(joins, fmt.Sprintf("JOIN %s AS %s ON %s.%s = l%d.%s AND %s.%s <= ?",
+				quote(step.LinkReference.GetDbTableName()), alias,
+				alias, quote(previousHashKey), index-1, quote(previousHashKey),
+				alias, quote(definition.LOAD_DATE)))
+			joinArgs = append(joinArgs, bridgeRefresh.SnapshotDate)
+		}
+
+		previousHashKey = hubHashKey
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s \n(%s) \nSELECT %s \nFROM %s AS l0 %s\n"+
+		"WHERE l0.%s <= ? AND NOT EXISTS (SELECT 1 FROM %s AS b WHERE b.%s = ? AND %s)",
+		quote(bridgeDef.GetDbTableName()), dialect.QuoteIdentifiers(sqlDialect, columns),
+		strings.Join(values, ", "),
+		quote(bridgeDef.Path[0].LinkReference.GetDbTableName()), strings.Join(joins, " \n"),
+		quote(definition.LOAD_DATE),
+		quote(bridgeDef.GetDbTableName()), quote(definition.SNAPSHOT_DATE),
+		strings.Join(existConditions, " AND "))
+
+	args := append(selectArgs, joinArgs...)
+	args = append(args, bridgeRefresh.SnapshotDate, bridgeRefresh.SnapshotDate)
+
+	return dialect.Rebind(sqlDialect, sql), args, nil
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//HubState is state of a hub entry; Attributes is merged from satelite rows
+//listed in Satelites, keyed by satelite attribute name
+type HubState struct {
+	HubName      string
+	HubRevision  int
+	HashKey      string
+	LoadDate     time.Time
+	RecordSource string
+	BusinessKeys map[string]string
+	Attributes   map[string]interface{}
+	Satelites    []SateliteState
+
+	attributeLoadDates map[string]time.Time
+}
+
+//HubQuery is query schema to read a hub entry by its hash key
+type HubQuery struct {
+	Definition *definition.HubDefinition
+	HashKey    string
+}
+
+//GenerateParamSQL is to generate parameterized select statement of hub entry
+func (hubQuery *HubQuery) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	if hubQuery.Definition == nil {
+		return "", nil, fmt.Errorf("hub query has no hub definition")
+	}
+
+	if hubQuery.HashKey == "" {
+		return "", nil, fmt.Errorf("hub query of %s has no hash key", hubQuery.Definition.Name)
+	}
+
+	columns := []string{definition.LOAD_DATE, definition.RECORD_SOURCE}
+	for _, businessKey := range hubQuery.Definition.BusinessKeys {
+		columns = append(columns, stringtool.ToSnakeCase(businessKey.Name))
+	}
+
+	sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?",
+		dialect.QuoteIdentifiers(sqlDialect, columns),
+		sqlDialect.QuoteIdentifier(hubQuery.Definition.GetDbTableName()),
+		sqlDialect.QuoteIdentifier(hubQuery.Definition.GetHashKey()))
+
+	return dialect.Rebind(sqlDialect, sql), []interface{}{hubQuery.HashKey}, nil
+}
+
+//ScanState is to read hub entry row selected by generated statement
+func (hubQuery *HubQuery) ScanState(scan func(dest ...interface{}) error) (*HubState, error) {
+	values := make([]interface{}, 2+len(hubQuery.Definition.BusinessKeys))
+	pointers := make([]interface{}, len(values))
+	for index := range values {
+		pointers[index] = &values[index]
+	}
+
+	if scanErr := scan(pointers...); scanErr != nil {
+		return nil, scanErr
+	}
+
+	loadDate, loadErr := convertTimeValue(values[0])
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	recordSource, _ := convertColumnValue(values[1], rdbmstool.CHAR)
+
+	state := HubState{
+		HubName:            hubQuery.Definition.Name,
+		HubRevision:        hubQuery.Definition.Revision,
+		HashKey:            hubQuery.HashKey,
+		LoadDate:           loadDate,
+		BusinessKeys:       make(map[string]string),
+		Attributes:         make(map[string]interface{}),
+		Satelites:          []SateliteState{},
+		attributeLoadDates: make(map[string]time.Time)}
+
+	if recordSource != nil {
+		state.RecordSource = recordSource.(string)
+	}
+
+	for index, businessKey := range hubQuery.Definition.BusinessKeys {
+		col, colErr := businessKey.GetBusinessKeyColumn()
+		if colErr != nil {
+			return nil, colErr
+		}
+
+		value, valueErr := convertColumnValue(values[2+index], col.DataType)
+		if valueErr != nil {
+			return nil, fmt.Errorf("business key %s: %s", businessKey.Name, valueErr.Error())
+		}
+
+		switch tmp := value.(type) {
+		case nil:
+			break
+		case string:
+			state.BusinessKeys[businessKey.Name] = tmp
+		case time.Time:
+			state.BusinessKeys[businessKey.Name] = tmp.Format("2006-01-02")
+		default:
+			state.BusinessKeys[businessKey.Name] = fmt.Sprintf("%v", tmp)
+		}
+	}
+
+	return &state, nil
+}
+
+//AddSatelite is to append satelite state and merge its attributes into hub state;
+//if same attribute name found in multiple satelites, value with latest load date is kept
+func (hubState *HubState) AddSatelite(satState SateliteState) {
+	hubState.Satelites = append(hubState.Satelites, satState)
+
+	if hubState.Attributes == nil {
+		hubState.Attributes = make(map[string]interface{})
+	}
+
+	if hubState.attributeLoadDates == nil {
+		hubState.attributeLoadDates = make(map[string]time.Time)
+	}
+
+	for name, value := range satState.Attributes {
+		if loadDate, ok := hubState.attributeLoadDates[name]; ok && loadDate.After(satState.LoadDate) {
+			continue
+		}
+
+		hubState.Attributes[name] = value
+		hubState.attributeLoadDates[name] = satState.LoadDate
+	}
+}
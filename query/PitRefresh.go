@@ -0,0 +1,73 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/record"
+	"github.com/guinso/rdbmstool"
+)
+
+//PitRefresh is schema to rebuild point in time (PIT) rows of a snapshot date
+type PitRefresh struct {
+	Definition   *definition.PitDefinition
+	SnapshotDate time.Time
+}
+
+//GenerateMultiParamSQL is to generate parameterized SQL statements which remove existing
+//PIT rows of snapshot date, then insert load date of valid satelite row (latest load date
+//not after snapshot date) for every hub hash key loaded before snapshot date
+func (pitRefresh *PitRefresh) GenerateMultiParamSQL(sqlDialect dialect.Dialect) ([]record.ParamSQL, error) {
+	pitDef := pitRefresh.Definition
+	if pitDef == nil || pitDef.HubReference == nil {
+		return nil, errors.New("PIT refresh has no PIT definition")
+	}
+
+	if pitDef.Satelites == nil || len(pitDef.Satelites) == 0 {
+		return nil, errors.New("PIT must has atleast one satelite reference")
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	hashKey := pitDef.HubReference.GetHashKey()
+
+	deleteSQL := fmt.Sprintf("DELETE FROM %s WHERE %s = ?",
+		quote(pitDef.GetDbTableName()), quote(definition.SNAPSHOT_DATE))
+
+	snapshotPlaceholder, placeholderErr := sqlDialect.TypedPlaceholder(rdbmstool.ColumnDefinition{
+		Name: definition.SNAPSHOT_DATE, DataType: rdbmstool.DATETIME})
+	if placeholderErr != nil {
+		return nil, placeholderErr
+	}
+
+	columns := []string{hashKey, definition.SNAPSHOT_DATE}
+	values := []string{"h." + quote(hashKey), snapshotPlaceholder}
+	args := []interface{}{pitRefresh.SnapshotDate}
+	for index, satRef := range pitDef.Satelites {
+		alias := fmt.Sprintf("s%d", index)
+
+		columns = append(columns, satRef.GetLoadDateColumn())
+		values = append(values, fmt.Sprintf(
+			"(SELECT MAX(%s.%s) FROM %s AS %s WHERE %s.%s = h.%s AND %s.%s <= ?)",
+			alias, quote(definition.LOAD_DATE), quote(satRef.GetDbTableName()), alias,
+			alias, quote(hashKey), quote(hashKey), alias, quote(definition.LOAD_DATE)))
+		args = append(args, pitRefresh.SnapshotDate)
+	}
+
+	insertSQL := fmt.Sprintf("INSERT INTO %s \n(%s) \nSELECT %s \nFROM %s AS h WHERE h.%s <= ?",
+		quote(pitDef.GetDbTableName()), dialect.QuoteIdentifiers(sqlDialect, columns),
+		strings.Join(values, ", "),
+		quote(pitDef.HubReference.GetDbTableName()), quote(definition.LOAD_DATE))
+	args = append(args, pitRefresh.SnapshotDate)
+
+	return []record.ParamSQL{
+		record.ParamSQL{
+			SQL:  dialect.Rebind(sqlDialect, deleteSQL),
+			Args: []interface{}{pitRefresh.SnapshotDate}},
+		record.ParamSQL{
+			SQL:  dialect.Rebind(sqlDialect, insertSQL),
+			Args: args}}, nil
+}
@@ -0,0 +1,186 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//SateliteState is a satelite row of a hub entry; Attributes is keyed by
+//satelite attribute name and typed by its attribute definition;
+//EndDate is nil if row is still open
+type SateliteState struct {
+	SateliteName string
+	Revision     int
+	LoadDate     time.Time
+	EndDate      *time.Time
+	RecordSource string
+	Attributes   map[string]interface{}
+}
+
+//SateliteQuery is query schema to read satelite row(s) of a hub (or link) hash key
+type SateliteQuery struct {
+	Definition *definition.SateliteDefinition
+	HashKey    string
+}
+
+//GenerateCurrentParamSQL is to generate parameterized select statement of current
+//(latest load date) satelite row; multi-active satelite has current row per multi-active key(s)
+func (satQuery *SateliteQuery) GenerateCurrentParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	if err := satQuery.validate(); err != nil {
+		return "", nil, err
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	sql := fmt.Sprintf("%s \nWHERE %s = ? AND %s = (%s)",
+		satQuery.selectSQL(sqlDialect),
+		quote(satQuery.Definition.GetParentHashKey()), quote(definition.LOAD_DATE),
+		satQuery.latestLoadDateSQL(sqlDialect, false))
+
+	return dialect.Rebind(sqlDialect, sql),
+		[]interface{}{satQuery.HashKey, satQuery.HashKey}, nil
+}
+
+//GenerateAsOfParamSQL is to generate parameterized select statement of satelite row
+//which is valid at given time, that is the row with latest load date not after it;
+//multi-active satelite has valid row per multi-active key(s)
+func (satQuery *SateliteQuery) GenerateAsOfParamSQL(sqlDialect dialect.Dialect,
+	asOf time.Time) (string, []interface{}, error) {
+	if err := satQuery.validate(); err != nil {
+		return "", nil, err
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	sql := fmt.Sprintf("%s \nWHERE %s = ? AND %s = (%s)",
+		satQuery.selectSQL(sqlDialect),
+		quote(satQuery.Definition.GetParentHashKey()), quote(definition.LOAD_DATE),
+		satQuery.latestLoadDateSQL(sqlDialect, true))
+
+	return dialect.Rebind(sqlDialect, sql),
+		[]interface{}{satQuery.HashKey, satQuery.HashKey, asOf}, nil
+}
+
+//GenerateHistoryParamSQL is to generate parameterized select statement of satelite rows
+//valid within given period, ordered by load date; it include row which is valid at
+//start of period; zero from or to time means the period is not bounded at that side
+func (satQuery *SateliteQuery) GenerateHistoryParamSQL(sqlDialect dialect.Dialect,
+	from time.Time, to time.Time) (string, []interface{}, error) {
+	if err := satQuery.validate(); err != nil {
+		return "", nil, err
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	hashKey := satQuery.Definition.GetParentHashKey()
+	sql := fmt.Sprintf("%s \nWHERE %s = ?", satQuery.selectSQL(sqlDialect), quote(hashKey))
+	args := []interface{}{satQuery.HashKey}
+
+	if !from.IsZero() {
+		sql = sql + fmt.Sprintf(" AND %s >= COALESCE((%s), ?)",
+			quote(definition.LOAD_DATE), satQuery.latestLoadDateSQL(sqlDialect, true))
+		args = append(args, satQuery.HashKey, from, from)
+	}
+
+	if !to.IsZero() {
+		sql = sql + fmt.Sprintf(" AND %s <= ?", quote(definition.LOAD_DATE))
+		args = append(args, to)
+	}
+
+	sql = sql + fmt.Sprintf(" \nORDER BY %s", quote(definition.LOAD_DATE))
+
+	return dialect.Rebind(sqlDialect, sql), args, nil
+}
+
+//ScanState is to read satelite row selected by generated statement
+func (satQuery *SateliteQuery) ScanState(scan func(dest ...interface{}) error) (*SateliteState, error) {
+	values := make([]interface{}, 3+len(satQuery.Definition.Attributes))
+	pointers := make([]interface{}, len(values))
+	for index := range values {
+		pointers[index] = &values[index]
+	}
+
+	if scanErr := scan(pointers...); scanErr != nil {
+		return nil, scanErr
+	}
+
+	loadDate, loadErr := convertTimeValue(values[0])
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	state := SateliteState{
+		SateliteName: satQuery.Definition.Name,
+		Revision:     satQuery.Definition.Revision,
+		LoadDate:     loadDate,
+		Attributes:   make(map[string]interface{})}
+
+	if values[1] != nil {
+		endDate, endErr := convertTimeValue(values[1])
+		if endErr != nil {
+			return nil, endErr
+		}
+		state.EndDate = &endDate
+	}
+
+	if recordSource, _ := convertColumnValue(values[2], rdbmstool.CHAR); recordSource != nil {
+		state.RecordSource = recordSource.(string)
+	}
+
+	for index, attr := range satQuery.Definition.Attributes {
+		value, valueErr := convertColumnValue(values[3+index], attr.DataType)
+		if valueErr != nil {
+			return nil, fmt.Errorf("satelite %s attribute %s: %s",
+				satQuery.Definition.Name, attr.Name, valueErr.Error())
+		}
+
+		state.Attributes[attr.Name] = value
+	}
+
+	return &state, nil
+}
+
+func (satQuery *SateliteQuery) validate() error {
+	if satQuery.Definition == nil {
+		return fmt.Errorf("satelite query has no satelite definition")
+	}
+
+	if satQuery.HashKey == "" {
+		return fmt.Errorf("satelite query of %s has no hub or link hash key", satQuery.Definition.Name)
+	}
+
+	return nil
+}
+
+//selectSQL is select clause of satelite row's columns
+func (satQuery *SateliteQuery) selectSQL(sqlDialect dialect.Dialect) string {
+	columns := []string{definition.LOAD_DATE, definition.END_DATE, definition.RECORD_SOURCE}
+	for _, attr := range satQuery.Definition.Attributes {
+		columns = append(columns, stringtool.ToSnakeCase(attr.Name))
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s", dialect.QuoteIdentifiers(sqlDialect, columns),
+		sqlDialect.QuoteIdentifier(satQuery.Definition.GetDbTableName()))
+}
+
+//latestLoadDateSQL is sub query of latest load date of hash key, optionally not after given time;
+//row of multi-active satelite is only compared with rows of the same multi-active key(s)
+func (satQuery *SateliteQuery) latestLoadDateSQL(sqlDialect dialect.Dialect, hasAsOf bool) string {
+	quote := sqlDialect.QuoteIdentifier
+	tableName := quote(satQuery.Definition.GetDbTableName())
+	sql := fmt.Sprintf("SELECT MAX(cur.%s) FROM %s AS cur WHERE cur.%s = ?",
+		quote(definition.LOAD_DATE), tableName, quote(satQuery.Definition.GetParentHashKey()))
+
+	for _, key := range satQuery.Definition.MultiActiveKeys {
+		column := quote(stringtool.ToSnakeCase(key))
+		sql = sql + fmt.Sprintf(" AND cur.%s = %s.%s", column, tableName, column)
+	}
+
+	if hasAsOf {
+		sql = sql + fmt.Sprintf(" AND cur.%s <= ?", quote(definition.LOAD_DATE))
+	}
+
+	return sql
+}
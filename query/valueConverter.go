@@ -0,0 +1,107 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guinso/rdbmstool"
+)
+
+//list of date time format returned by database driver as text
+var dateTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02"}
+
+//convertColumnValue convert raw value scanned from database into Go value of given
+//column datatype: string, int, float64, bool or time.Time; null value is returned as nil
+func convertColumnValue(raw interface{}, dataType rdbmstool.ColumnDataType) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	if tmpBytes, ok := raw.([]byte); ok {
+		raw = string(tmpBytes)
+	}
+
+	switch dataType {
+	case rdbmstool.CHAR, rdbmstool.VARCHAR, rdbmstool.TEXT:
+		switch tmp := raw.(type) {
+		case string:
+			if dataType == rdbmstool.CHAR {
+				//some database vendor pad fixed length text with spaces
+				return strings.TrimRight(tmp, " "), nil
+			}
+			return tmp, nil
+		default:
+			return fmt.Sprintf("%v", tmp), nil
+		}
+
+	case rdbmstool.INTEGER:
+		switch tmp := raw.(type) {
+		case int64:
+			return int(tmp), nil
+		case int:
+			return tmp, nil
+		case string:
+			return strconv.Atoi(strings.TrimSpace(tmp))
+		}
+
+	case rdbmstool.DECIMAL, rdbmstool.FLOAT:
+		switch tmp := raw.(type) {
+		case float64:
+			return tmp, nil
+		case float32:
+			return float64(tmp), nil
+		case int64:
+			return float64(tmp), nil
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(tmp), 64)
+		}
+
+	case rdbmstool.BOOLEAN:
+		switch tmp := raw.(type) {
+		case bool:
+			return tmp, nil
+		case int64:
+			return tmp != 0, nil
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(tmp))
+		}
+
+	case rdbmstool.DATE, rdbmstool.DATETIME:
+		switch tmp := raw.(type) {
+		case time.Time:
+			return tmp, nil
+		case string:
+			for _, format := range dateTimeFormats {
+				if tmpTime, err := time.Parse(format, strings.TrimSpace(tmp)); err == nil {
+					return tmpTime, nil
+				}
+			}
+			return nil, fmt.Errorf("unable to parse date time value: %s", tmp)
+		}
+	}
+
+	return nil, fmt.Errorf("unable to convert %T value into %s datatype", raw, dataType.String())
+}
+
+//convertTimeValue convert raw value scanned from database into time.Time
+func convertTimeValue(raw interface{}) (time.Time, error) {
+	value, err := convertColumnValue(raw, rdbmstool.DATETIME)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if value == nil {
+		return time.Time{}, fmt.Errorf("date time value cannot be null")
+	}
+
+	return value.(time.Time), nil
+}
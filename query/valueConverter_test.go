@@ -0,0 +1,41 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guinso/rdbmstool"
+)
+
+func TestConvertColumnValue(t *testing.T) {
+	cases := []struct {
+		raw      interface{}
+		dataType rdbmstool.ColumnDataType
+		expected interface{}
+	}{
+		{[]byte("42"), rdbmstool.INTEGER, 42},
+		{int64(7), rdbmstool.INTEGER, 7},
+		{[]byte("12.50"), rdbmstool.DECIMAL, 12.5},
+		{int64(1), rdbmstool.BOOLEAN, true},
+		{"ACME  ", rdbmstool.CHAR, "ACME"},
+		{[]byte("2017-08-03 10:00:00"), rdbmstool.DATETIME,
+			time.Date(2017, 8, 3, 10, 0, 0, 0, time.UTC)},
+		{nil, rdbmstool.TEXT, nil}}
+
+	for _, testCase := range cases {
+		value, err := convertColumnValue(testCase.raw, testCase.dataType)
+		if err != nil {
+			t.Error(err.Error())
+			continue
+		}
+
+		if value != testCase.expected {
+			t.Errorf("Expect %v (%T), given %v (%T) instead",
+				testCase.expected, testCase.expected, value, value)
+		}
+	}
+
+	if _, err := convertColumnValue("abc", rdbmstool.INTEGER); err == nil {
+		t.Error("Expect error for invalid integer value")
+	}
+}
@@ -1,23 +1,53 @@
 package record
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/dvmeta"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
 )
 
-// DvInsertRecord is datavault insert record schema
+// DvInsertRecord is datavault insert record schema;
+// Hasher is optional, default hasher (MD5) is used to fill missing hash keys;
+// SkipUnchangedSatelites skip satelite record (with hash diff) which is identical to current row;
+// SkipExistingKeys skip hub and link record which hash key already exists in database;
+// Dialect is SQL dialect of generated parameterized SQL statements; MySQL is used if nil
 type DvInsertRecord struct {
-	LoadDate time.Time
+	LoadDate               time.Time
+	Dialect                dialect.Dialect
+	Hasher                 *hashkey.Hasher
+	SkipUnchangedSatelites bool
+	SkipExistingKeys       bool
+
+	Hubs                 []HubInsertRecord
+	Links                []LinkInsertRecord
+	Satelites            []SateliteInsertRecord
+	EffectivitySatelites []EffectivitySateliteInsertRecord
+	References           []ReferenceInsertRecord
+	NonHistorizedLinks   []NonHistorizedLinkInsertRecord
+}
 
-	Hubs      []HubInsertRecord
-	Links     []LinkInsertRecord
-	Satelites []SateliteInsertRecord
+//ParamSQL is parameterized SQL statement with its ordered argument list
+type ParamSQL struct {
+	SQL  string
+	Args []interface{}
 }
 
 //GenerateSQL is to generate SQL statement to represent a set of entities record
 func (dv *DvInsertRecord) GenerateSQL() (string, error) {
 
-	integrateErr := dv.checkIntegrity()
+	if hashErr := dv.FillHashKeys(); hashErr != nil {
+		return "", hashErr
+	}
+
+	integrateErr := dv.checkIntegrity(nil, nil)
 	if integrateErr != nil {
 		return "", fmt.Errorf(
 			"Unable to generate datavault insert record, "+
@@ -27,6 +57,19 @@ func (dv *DvInsertRecord) GenerateSQL() (string, error) {
 
 	var SQLstatement string
 
+	//generate Reference SQL
+	for _, ref := range dv.References {
+		refSQL, refErr := ref.GenerateSQL()
+
+		if refErr != nil {
+			return "", fmt.Errorf("Unable to generate insert SQL statement for entity Reference %s:\n%s",
+				ref.ReferenceName,
+				refErr.Error())
+		}
+
+		SQLstatement = SQLstatement + refSQL + ";\n"
+	}
+
 	//generate HUB SQL
 	for _, hub := range dv.Hubs {
 		hubSQL, hubErr := hub.GenerateSQL()
@@ -53,6 +96,19 @@ func (dv *DvInsertRecord) GenerateSQL() (string, error) {
 		SQLstatement = SQLstatement + linkSQL + ";\n"
 	}
 
+	//generate Non-historized LINK SQL
+	for _, nhl := range dv.NonHistorizedLinks {
+		nhlSQL, nhlErr := nhl.GenerateSQL()
+
+		if nhlErr != nil {
+			return "", fmt.Errorf("Unable to generate insert SQL statement for entity Non-historized Link %s:\n%s",
+				nhl.LinkName,
+				nhlErr.Error())
+		}
+
+		SQLstatement = SQLstatement + nhlSQL + ";\n"
+	}
+
 	//generate Satelite SQL
 	for _, sat := range dv.Satelites {
 		satSQL, satErr := sat.GenerateSQL()
@@ -66,13 +122,22 @@ func (dv *DvInsertRecord) GenerateSQL() (string, error) {
 		SQLstatement = SQLstatement + satSQL + ";\n"
 	}
 
+	if len(dv.EffectivitySatelites) > 0 {
+		return "", errors.New("Unable to generate insert SQL statement for effectivity satelite, " +
+			"use GenerateMultiParamSQL instead")
+	}
+
 	return SQLstatement, nil
 }
 
 //GenerateSQL is to generate SQL statement to represent a set of entities record
 func (dv *DvInsertRecord) GenerateMultiSQL() ([]string, error) {
 
-	integrateErr := dv.checkIntegrity()
+	if hashErr := dv.FillHashKeys(); hashErr != nil {
+		return nil, hashErr
+	}
+
+	integrateErr := dv.checkIntegrity(nil, nil)
 	if integrateErr != nil {
 		return nil, fmt.Errorf(
 			"Unable to generate datavault insert record, "+
@@ -82,6 +147,19 @@ func (dv *DvInsertRecord) GenerateMultiSQL() ([]string, error) {
 
 	var SQLstatement []string
 
+	//generate Reference SQL
+	for _, ref := range dv.References {
+		refSQL, refErr := ref.GenerateSQL()
+
+		if refErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Reference %s:\n%s",
+				ref.ReferenceName,
+				refErr.Error())
+		}
+
+		SQLstatement = append(SQLstatement, refSQL)
+	}
+
 	//generate HUB SQL
 	for _, hub := range dv.Hubs {
 		hubSQL, hubErr := hub.GenerateSQL()
@@ -108,6 +186,19 @@ func (dv *DvInsertRecord) GenerateMultiSQL() ([]string, error) {
 		SQLstatement = append(SQLstatement, linkSQL)
 	}
 
+	//generate Non-historized LINK SQL
+	for _, nhl := range dv.NonHistorizedLinks {
+		nhlSQL, nhlErr := nhl.GenerateSQL()
+
+		if nhlErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Non-historized Link %s:\n%s",
+				nhl.LinkName,
+				nhlErr.Error())
+		}
+
+		SQLstatement = append(SQLstatement, nhlSQL)
+	}
+
 	//generate Satelite SQL
 	for _, sat := range dv.Satelites {
 		satSQL, satErr := sat.GenerateSQL()
@@ -121,16 +212,539 @@ func (dv *DvInsertRecord) GenerateMultiSQL() ([]string, error) {
 		SQLstatement = append(SQLstatement, satSQL)
 	}
 
+	if len(dv.EffectivitySatelites) > 0 {
+		return nil, errors.New("Unable to generate insert SQL statement for effectivity satelite, " +
+			"use GenerateMultiParamSQL instead")
+	}
+
 	return SQLstatement, nil
 }
 
-func (dv *DvInsertRecord) checkIntegrity() error {
-	//TODO check integrity
-	//
-	//  Hub, Link, and Satelite must has hash key value
-	//  Satelite has valid hash key reference
-	//  Link has valid hash key reference
-	//  No duplicate hub hash key been used
-	//	All Hub, Link, and Satelite name is valid (exists in database)
+//GenerateMultiParamSQL is to generate parameterized SQL statements to represent a set of entities record;
+//each satelite record is preceded by statement which end date previous open row, while each
+//effectivity satelite record is preceded by statement which close driving key's relationship with other partner
+func (dv *DvInsertRecord) GenerateMultiParamSQL() ([]ParamSQL, error) {
+
+	if hashErr := dv.FillHashKeys(); hashErr != nil {
+		return nil, hashErr
+	}
+
+	integrateErr := dv.checkIntegrity(nil, nil)
+	if integrateErr != nil {
+		return nil, fmt.Errorf(
+			"Unable to generate datavault insert record, "+
+				"integrity fail:\n%s",
+			integrateErr.Error())
+	}
+
+	var statements []ParamSQL
+
+	//generate Reference SQL
+	for _, ref := range dv.References {
+		var refSQL string
+		var refArgs []interface{}
+		var refErr error
+		if dv.SkipExistingKeys {
+			refSQL, refArgs, refErr = ref.GenerateIdempotentParamSQL(dv.Dialect)
+		} else {
+			refSQL, refArgs, refErr = ref.GenerateParamSQL(dv.Dialect)
+		}
+
+		if refErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Reference %s:\n%s",
+				ref.ReferenceName,
+				refErr.Error())
+		}
+
+		statements = append(statements, ParamSQL{SQL: refSQL, Args: refArgs})
+	}
+
+	//generate HUB SQL
+	for _, hub := range dv.Hubs {
+		var hubSQL string
+		var hubArgs []interface{}
+		var hubErr error
+		if dv.SkipExistingKeys {
+			hubSQL, hubArgs, hubErr = hub.GenerateIdempotentParamSQL(dv.Dialect)
+		} else {
+			hubSQL, hubArgs, hubErr = hub.GenerateParamSQL(dv.Dialect)
+		}
+
+		if hubErr != nil {
+			return nil, fmt.Errorf(
+				"Unable to generate insert SQL statement for entity HUB %s:\n%s",
+				hub.HubName, hubErr.Error())
+		}
+
+		statements = append(statements, ParamSQL{SQL: hubSQL, Args: hubArgs})
+	}
+
+	//generate LINK SQL
+	for _, link := range dv.Links {
+		var linkSQL string
+		var linkArgs []interface{}
+		var linkErr error
+		if dv.SkipExistingKeys {
+			linkSQL, linkArgs, linkErr = link.GenerateIdempotentParamSQL(dv.Dialect)
+		} else {
+			linkSQL, linkArgs, linkErr = link.GenerateParamSQL(dv.Dialect)
+		}
+
+		if linkErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Link %s:\n%s",
+				link.LinkName,
+				linkErr.Error())
+		}
+
+		statements = append(statements, ParamSQL{SQL: linkSQL, Args: linkArgs})
+	}
+
+	//generate Non-historized LINK SQL
+	for _, nhl := range dv.NonHistorizedLinks {
+		var nhlSQL string
+		var nhlArgs []interface{}
+		var nhlErr error
+		if dv.SkipExistingKeys {
+			nhlSQL, nhlArgs, nhlErr = nhl.GenerateIdempotentParamSQL(dv.Dialect)
+		} else {
+			nhlSQL, nhlArgs, nhlErr = nhl.GenerateParamSQL(dv.Dialect)
+		}
+
+		if nhlErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Non-historized Link %s:\n%s",
+				nhl.LinkName,
+				nhlErr.Error())
+		}
+
+		statements = append(statements, ParamSQL{SQL: nhlSQL, Args: nhlArgs})
+	}
+
+	//generate Satelite SQL
+	for _, sat := range dv.Satelites {
+		skipUnchanged := dv.SkipUnchangedSatelites && sat.HasHashDiff
+
+		//close previous open row before insert new row
+		endSQL, endArgs, endErr := sat.GenerateEndDateParamSQL(dv.Dialect, skipUnchanged)
+		if endErr != nil {
+			return nil, fmt.Errorf("Unable to generate end date SQL statement for entity Satelite %s:\n%s",
+				sat.SateliteName,
+				endErr.Error())
+		}
+
+		statements = append(statements, ParamSQL{SQL: endSQL, Args: endArgs})
+
+		var satSQL string
+		var satArgs []interface{}
+		var satErr error
+		if skipUnchanged {
+			satSQL, satArgs, satErr = sat.GenerateChangedParamSQL(dv.Dialect)
+		} else {
+			satSQL, satArgs, satErr = sat.GenerateParamSQL(dv.Dialect)
+		}
+
+		if satErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Satelite %s:\n%s",
+				sat.SateliteName,
+				satErr.Error())
+		}
+
+		statements = append(statements, ParamSQL{SQL: satSQL, Args: satArgs})
+	}
+
+	//generate Effectivity Satelite SQL
+	for _, esat := range dv.EffectivitySatelites {
+		endSQL, endArgs, endErr := esat.GenerateEndDateParamSQL(dv.Dialect)
+		if endErr != nil {
+			return nil, fmt.Errorf("Unable to generate end date SQL statement for entity Effectivity Satelite %s:\n%s",
+				esat.SateliteName,
+				endErr.Error())
+		}
+
+		esatSQL, esatArgs, esatErr := esat.GenerateParamSQL(dv.Dialect)
+		if esatErr != nil {
+			return nil, fmt.Errorf("Unable to generate insert SQL statement for entity Effectivity Satelite %s:\n%s",
+				esat.SateliteName,
+				esatErr.Error())
+		}
+
+		statements = append(statements,
+			ParamSQL{SQL: endSQL, Args: endArgs},
+			ParamSQL{SQL: esatSQL, Args: esatArgs})
+	}
+
+	return statements, nil
+}
+
+//FillHashKeys compute all missing hub, link and satelite hash keys with insert record's hasher
+func (dv *DvInsertRecord) FillHashKeys() error {
+	for index := range dv.Hubs {
+		if hashErr := dv.Hubs[index].FillHashKey(dv.Hasher); hashErr != nil {
+			return hashErr
+		}
+	}
+
+	for index := range dv.Links {
+		if hashErr := dv.Links[index].FillHashKey(dv.Hasher); hashErr != nil {
+			return hashErr
+		}
+	}
+
+	for index := range dv.Satelites {
+		if hashErr := dv.Satelites[index].FillHashKey(dv.Hasher); hashErr != nil {
+			return hashErr
+		}
+	}
+
+	for index := range dv.EffectivitySatelites {
+		if hashErr := dv.EffectivitySatelites[index].FillHashKey(dv.Hasher); hashErr != nil {
+			return hashErr
+		}
+	}
+
+	for index := range dv.NonHistorizedLinks {
+		if hashErr := dv.NonHistorizedLinks[index].FillHashKey(dv.Hasher); hashErr != nil {
+			return hashErr
+		}
+	}
+
+	return nil
+}
+
+//CheckIntegrity validate insert record against data vault in database before insert;
+//missing hash keys are filled before validation and all violation(s) are
+//reported as IntegrityError
+func (dv *DvInsertRecord) CheckIntegrity(metaReader dvmeta.DataVaultMetaReader,
+	dbHandler rdbmstool.DbHandlerProxy) error {
+	//fill failure is reported as missing hash key violation
+	for index := range dv.Hubs {
+		dv.Hubs[index].FillHashKey(dv.Hasher)
+	}
+	for index := range dv.Links {
+		dv.Links[index].FillHashKey(dv.Hasher)
+	}
+	for index := range dv.Satelites {
+		dv.Satelites[index].FillHashKey(dv.Hasher)
+	}
+	for index := range dv.EffectivitySatelites {
+		dv.EffectivitySatelites[index].FillHashKey(dv.Hasher)
+	}
+	for index := range dv.NonHistorizedLinks {
+		dv.NonHistorizedLinks[index].FillHashKey(dv.Hasher)
+	}
+
+	return dv.checkIntegrity(metaReader, dbHandler)
+}
+
+//checkIntegrity validate insert record; checks which require database are
+//skipped if either metaReader or dbHandler is nil
+//
+//  Hub, Link, and Satelite must has hash key value
+//  Satelite has valid hash key reference
+//  Link has valid hash key reference
+//  Effectivity satelite has valid link hash key reference
+//  Satelite has the same multi-active key(s) as its definition
+//  Non-historized link has valid hash key reference
+//  No duplicate hub or link hash key been used
+//	All Hub, Link, and Satelite name is valid (exists in database)
+func (dv *DvInsertRecord) checkIntegrity(metaReader dvmeta.DataVaultMetaReader,
+	dbHandler rdbmstool.DbHandlerProxy) error {
+	integrityErr := IntegrityError{}
+	checkDb := metaReader != nil && dbHandler != nil
+
+	//hub and link hash key of current batch; key: db table name, value: hash keys
+	batchKeys := make(map[string]map[string]bool)
+
+	for _, hub := range dv.Hubs {
+		hubRef := definition.HubReference{HubName: hub.HubName, Revision: hub.HubRevision}
+
+		if checkDb {
+			if _, hubErr := metaReader.GetHubDefinition(
+				hub.HubName, hub.HubRevision, dbHandler); hubErr != nil {
+				integrityErr.add("hub %s revision %d not found: %s",
+					hub.HubName, hub.HubRevision, hubErr.Error())
+			}
+		}
+
+		if hub.HashKey == "" {
+			integrityErr.add("hub %s revision %d has no hash key", hub.HubName, hub.HubRevision)
+			continue
+		}
+
+		if !addBatchHashKey(batchKeys, hubRef.GetDbTableName(), hub.HashKey) {
+			integrityErr.add("hub %s revision %d has duplicate hash key %s",
+				hub.HubName, hub.HubRevision, hub.HashKey)
+		}
+	}
+
+	for _, link := range dv.Links {
+		if link.HashKey == "" {
+			integrityErr.add("link %s revision %d has no hash key", link.LinkName, link.LinkRevision)
+		} else if !addBatchHashKey(batchKeys,
+			definition.LinkTableName(link.LinkName, link.LinkRevision), link.HashKey) {
+			integrityErr.add("link %s revision %d has duplicate hash key %s",
+				link.LinkName, link.LinkRevision, link.HashKey)
+		}
+
+		var linkDef *definition.LinkDefinition
+		if checkDb {
+			var linkErr error
+			linkDef, linkErr = metaReader.GetLinkDefinition(link.LinkName, link.LinkRevision, dbHandler)
+			if linkErr != nil {
+				integrityErr.add("link %s revision %d not found: %s",
+					link.LinkName, link.LinkRevision, linkErr.Error())
+			}
+		}
+
+		var hubRefs []definition.HubReference
+		if linkDef != nil {
+			hubRefs = linkDef.HubReferences
+		}
+		dv.checkHubReferences(&integrityErr, dbHandler, batchKeys, "link", link.LinkName,
+			link.LinkRevision, link.ReferenceHashKey, hubRefs)
+	}
+
+	for _, nhl := range dv.NonHistorizedLinks {
+		if nhl.HashKey == "" {
+			integrityErr.add("non-historized link %s revision %d has no hash key", nhl.LinkName, nhl.LinkRevision)
+		} else if !addBatchHashKey(batchKeys,
+			definition.NonHistorizedLinkTableName(nhl.LinkName, nhl.LinkRevision), nhl.HashKey) {
+			integrityErr.add("non-historized link %s revision %d has duplicate hash key %s",
+				nhl.LinkName, nhl.LinkRevision, nhl.HashKey)
+		}
+
+		var hubRefs []definition.HubReference
+		if checkDb {
+			nhlDef, nhlErr := metaReader.GetNonHistorizedLinkDefinition(nhl.LinkName, nhl.LinkRevision, dbHandler)
+			if nhlErr != nil {
+				integrityErr.add("non-historized link %s revision %d not found: %s",
+					nhl.LinkName, nhl.LinkRevision, nhlErr.Error())
+			} else {
+				hubRefs = nhlDef.HubReferences
+			}
+		}
+
+		dv.checkHubReferences(&integrityErr, dbHandler, batchKeys, "non-historized link", nhl.LinkName,
+			nhl.LinkRevision, nhl.ReferenceHashKey, hubRefs)
+	}
+
+	if checkDb {
+		for _, ref := range dv.References {
+			if _, refErr := metaReader.GetReferenceDefinition(ref.ReferenceName, ref.Revision, dbHandler); refErr != nil {
+				integrityErr.add("reference %s revision %d not found: %s",
+					ref.ReferenceName, ref.Revision, refErr.Error())
+			}
+		}
+	}
+
+	for _, sat := range dv.Satelites {
+		if sat.getHashKeyValue() == "" {
+			integrityErr.add("satelite %s revision %d has no hub or link hash key",
+				sat.SateliteName, sat.Revision)
+		}
+
+		if !checkDb {
+			continue
+		}
+
+		satDef, satErr := metaReader.GetSateliteDefinition(sat.SateliteName, sat.Revision, dbHandler)
+		if satErr != nil {
+			integrityErr.add("satelite %s revision %d not found: %s",
+				sat.SateliteName, sat.Revision, satErr.Error())
+			continue
+		}
+
+		parentName := sat.HubName
+		if sat.isLinkSatelite() {
+			parentName = sat.LinkName
+		}
+
+		if !isSameParent(satDef, sat) {
+			integrityErr.add("satelite %s revision %d refer to %s but given %s instead",
+				sat.SateliteName, sat.Revision, satDef.GetParentDbTableName(), parentName)
+			continue
+		}
+
+		if !isSameMultiActiveKeys(satDef.MultiActiveKeys, sat.MultiActiveKeys) {
+			integrityErr.add("satelite %s revision %d has multi-active key %v but given %v instead",
+				sat.SateliteName, sat.Revision, satDef.MultiActiveKeys, sat.MultiActiveKeys)
+		}
+
+		if sat.getHashKeyValue() == "" {
+			continue
+		}
+
+		if refErr := checkHashKey(dv.Dialect, dbHandler, batchKeys, satDef.GetParentDbTableName(),
+			satDef.GetParentHashKey(), sat.getHashKeyValue()); refErr != nil {
+			integrityErr.add("satelite %s revision %d refer to unknown %s hash key %s: %s",
+				sat.SateliteName, sat.Revision, parentName, sat.getHashKeyValue(), refErr.Error())
+		}
+	}
+
+	for _, esat := range dv.EffectivitySatelites {
+		if esat.LinkHashKeyValue == "" || esat.DrivingKeyHashKeyValue == "" {
+			integrityErr.add("effectivity satelite %s revision %d has no link or driving key hash key",
+				esat.SateliteName, esat.Revision)
+			continue
+		}
+
+		if !checkDb {
+			continue
+		}
+
+		esatDef, esatErr := metaReader.GetEffectivitySateliteDefinition(
+			esat.SateliteName, esat.Revision, dbHandler)
+		if esatErr != nil {
+			integrityErr.add("effectivity satelite %s revision %d not found: %s",
+				esat.SateliteName, esat.Revision, esatErr.Error())
+			continue
+		}
+
+		if strings.Compare(stringtool.ToSnakeCase(esatDef.LinkReference.LinkName),
+			stringtool.ToSnakeCase(esat.LinkName)) != 0 ||
+			strings.Compare(esatDef.DrivingKey.GetColumnName(), esat.getDrivingKeyDbColumnName()) != 0 {
+			integrityErr.add("effectivity satelite %s revision %d refer to link %s driving key %s "+
+				"but given %s and %s instead", esat.SateliteName, esat.Revision,
+				esatDef.LinkReference.LinkName, esatDef.DrivingKey.GetColumnName(),
+				esat.LinkName, esat.getDrivingKeyDbColumnName())
+			continue
+		}
+
+		if refErr := checkHashKey(dv.Dialect, dbHandler, batchKeys, esatDef.LinkReference.GetDbTableName(),
+			esatDef.LinkReference.GetHashKey(), esat.LinkHashKeyValue); refErr != nil {
+			integrityErr.add("effectivity satelite %s revision %d refer to unknown link %s hash key %s: %s",
+				esat.SateliteName, esat.Revision, esat.LinkName, esat.LinkHashKeyValue, refErr.Error())
+		}
+	}
+
+	if len(integrityErr.Violations) > 0 {
+		return &integrityErr
+	}
+
+	return nil
+}
+
+func findHubReference(hubRefs []definition.HubReference, hubName string) *definition.HubReference {
+	for index := range hubRefs {
+		if strings.Compare(stringtool.ToSnakeCase(hubRefs[index].HubName),
+			stringtool.ToSnakeCase(hubName)) == 0 {
+			return &hubRefs[index]
+		}
+	}
+
+	return nil
+}
+
+//checkHubReferences verify hub reference hash key(s) of link (or non-historized link) record;
+//database checks are skipped if hubRefs (hub references of link definition) is nil
+func (dv *DvInsertRecord) checkHubReferences(integrityErr *IntegrityError, dbHandler rdbmstool.DbHandlerProxy,
+	batchKeys map[string]map[string]bool, entity string, linkName string, revision int,
+	refs []LinkReferenceInsertRecord, hubRefs []definition.HubReference) {
+	for _, ref := range refs {
+		if ref.HashKeyValue == "" {
+			integrityErr.add("%s %s revision %d has no hash key for hub %s",
+				entity, linkName, revision, ref.HubName)
+			continue
+		}
+
+		if hubRefs == nil {
+			continue
+		}
+
+		hubRef := findLinkHubReference(hubRefs, ref)
+		if hubRef == nil {
+			integrityErr.add("%s %s revision %d has no reference to hub %s (column %s)",
+				entity, linkName, revision, ref.HubName, ref.getHashKeyDbColumnName())
+			continue
+		}
+
+		if refErr := checkHashKey(dv.Dialect, dbHandler, batchKeys,
+			hubRef.GetDbTableName(), hubRef.GetHashKey(), ref.HashKeyValue); refErr != nil {
+			integrityErr.add("%s %s revision %d refer to unknown hub %s hash key %s: %s",
+				entity, linkName, revision, ref.HubName, ref.HashKeyValue, refErr.Error())
+		}
+	}
+}
+
+//findLinkHubReference search link's hub reference which has the same column
+//(hub name and role) as given insert record's reference
+func findLinkHubReference(hubRefs []definition.HubReference, ref LinkReferenceInsertRecord) *definition.HubReference {
+	for index := range hubRefs {
+		if strings.Compare(hubRefs[index].GetColumnName(), ref.getHashKeyDbColumnName()) == 0 {
+			return &hubRefs[index]
+		}
+	}
+
+	return nil
+}
+
+//isSameParent check satelite insert record refer to same hub or link as satelite definition
+func isSameParent(satDef *definition.SateliteDefinition, sat SateliteInsertRecord) bool {
+	if sat.isLinkSatelite() {
+		return satDef.LinkReference != nil &&
+			strings.Compare(stringtool.ToSnakeCase(satDef.LinkReference.LinkName),
+				stringtool.ToSnakeCase(sat.LinkName)) == 0
+	}
+
+	return satDef.HubReference != nil &&
+		findHubReference([]definition.HubReference{*satDef.HubReference}, sat.HubName) != nil
+}
+
+//isSameMultiActiveKeys check insert record has the same multi-active key(s) as satelite definition
+func isSameMultiActiveKeys(defKeys []string, recordKeys []string) bool {
+	if len(defKeys) != len(recordKeys) {
+		return false
+	}
+
+	keys := make(map[string]bool)
+	for _, key := range defKeys {
+		keys[stringtool.ToSnakeCase(key)] = true
+	}
+
+	for _, key := range recordKeys {
+		if !keys[stringtool.ToSnakeCase(key)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+//addBatchHashKey register hash key of current batch; return false if it is already registered
+func addBatchHashKey(batchKeys map[string]map[string]bool, tableName string, hashKey string) bool {
+	hashKeys, ok := batchKeys[tableName]
+	if !ok {
+		hashKeys = make(map[string]bool)
+		batchKeys[tableName] = hashKeys
+	}
+
+	if hashKeys[hashKey] {
+		return false
+	}
+	hashKeys[hashKey] = true
+
+	return true
+}
+
+//checkHashKey verify hub (or link) hash key exists either in current batch or database
+func checkHashKey(sqlDialect dialect.Dialect, dbHandler rdbmstool.DbHandlerProxy, batchKeys map[string]map[string]bool,
+	tableName string, hashKeyColumn string, hashKey string) error {
+	if batchKeys[tableName][hashKey] {
+		return nil
+	}
+
+	sqlDialect = getDialect(sqlDialect)
+
+	var count int
+	queryErr := dbHandler.QueryRow(dialect.Rebind(sqlDialect, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?",
+		sqlDialect.QuoteIdentifier(tableName),
+		sqlDialect.QuoteIdentifier(hashKeyColumn))), hashKey).Scan(&count)
+	if queryErr != nil {
+		return queryErr
+	}
+
+	if count == 0 {
+		return errors.New("hash key not found in database")
+	}
+
 	return nil
 }
@@ -0,0 +1,93 @@
+package record
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	dv := DvInsertRecord{
+		Hubs: []HubInsertRecord{
+			HubInsertRecord{HubName: "Invoice", HashKey: "0123456789abcdef0123456789abcdef"},
+			HubInsertRecord{HubName: "Invoice", HashKey: "0123456789abcdef0123456789abcdef"}},
+		Links: []LinkInsertRecord{
+			LinkInsertRecord{
+				LinkName: "InvoiceCustomer",
+				ReferenceHashKey: []LinkReferenceInsertRecord{
+					LinkReferenceInsertRecord{HubName: "Invoice"},
+					LinkReferenceInsertRecord{HubName: "Customer"}}}},
+		Satelites: []SateliteInsertRecord{
+			SateliteInsertRecord{SateliteName: "Invoice", HubName: "Invoice"}}}
+
+	err := dv.CheckIntegrity(nil, nil)
+	if err == nil {
+		t.Error("Expect integrity check fail")
+		return
+	}
+
+	integrityErr, ok := err.(*IntegrityError)
+	if !ok {
+		t.Errorf("Expect IntegrityError, given %T instead", err)
+		return
+	}
+
+	//duplicate hub, link hash key, 2 link references, satelite hash key
+	if len(integrityErr.Violations) != 5 {
+		t.Errorf("Expect 5 violations, given %d instead:\n%s",
+			len(integrityErr.Violations), integrityErr.Error())
+	}
+}
+
+func TestCheckIntegrityDuplicateLink(t *testing.T) {
+	invoice := HubInsertRecord{HubName: "Invoice", BusinessKeyVues: []HubBusinessKeyInsertRecord{
+		HubBusinessKeyInsertRecord{BusinessKey: "InvoiceNo", BusinessValue: "INV-001"}}}
+	customer := HubInsertRecord{HubName: "Customer", BusinessKeyVues: []HubBusinessKeyInsertRecord{
+		HubBusinessKeyInsertRecord{BusinessKey: "Name", BusinessValue: "ACME"}}}
+	link := LinkInsertRecord{
+		LinkName: "InvoiceCustomer",
+		ReferenceHashKey: []LinkReferenceInsertRecord{
+			LinkReferenceInsertRecord{HubName: "Invoice", BusinessKeyValues: invoice.BusinessKeyVues},
+			LinkReferenceInsertRecord{HubName: "Customer", BusinessKeyValues: customer.BusinessKeyVues}}}
+
+	dv := DvInsertRecord{
+		Hubs:  []HubInsertRecord{invoice, customer},
+		Links: []LinkInsertRecord{link, link}}
+
+	err := dv.CheckIntegrity(nil, nil)
+	integrityErr, ok := err.(*IntegrityError)
+	if !ok {
+		t.Errorf("Expect IntegrityError, given %v instead", err)
+		return
+	}
+
+	if len(integrityErr.Violations) != 1 ||
+		!strings.HasPrefix(integrityErr.Violations[0], "link InvoiceCustomer revision 0 has duplicate hash key") {
+		t.Errorf("Expect duplicate link hash key violation, given:\n%s", integrityErr.Error())
+	}
+}
+
+func TestGenerateMultiParamSQLEndDate(t *testing.T) {
+	sat := createTestSateliteInsertRecord()
+	dv := DvInsertRecord{
+		SkipUnchangedSatelites: true,
+		Satelites:              []SateliteInsertRecord{sat}}
+
+	statements, err := dv.GenerateMultiParamSQL()
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(statements) != 2 {
+		t.Errorf("Expect 2 statements (end date and insert), given %d instead", len(statements))
+		return
+	}
+
+	if !strings.HasPrefix(statements[0].SQL, "UPDATE `sat_invoice_rev0` SET `end_date` = ?") {
+		t.Errorf("Expect first statement end date previous row, given %s instead", statements[0].SQL)
+	}
+
+	if statements[0].Args[0] != sat.LoadDate {
+		t.Errorf("Expect end date is %v, given %v instead", sat.LoadDate, statements[0].Args[0])
+	}
+}
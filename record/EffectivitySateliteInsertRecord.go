@@ -0,0 +1,182 @@
+package record
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//EffectivitySateliteInsertRecord is effectivity satelite insert record schema which mark
+//link relationship effective since StartDate (LoadDate is used if StartDate is not provided);
+//previous relationship of the same driving key is closed once it has a new partner;
+//DrivingKeyRole is required if link refer to the same hub more than once (must match effectivity satelite definition);
+//LinkReferences is optional, used to compute link and driving key hash key value if they are not provided
+type EffectivitySateliteInsertRecord struct {
+	SateliteName           string
+	Revision               int
+	RecordSource           string
+	LinkName               string
+	LinkHashKeyValue       string
+	LinkReferences         []LinkReferenceInsertRecord
+	DrivingKeyHubName      string
+	DrivingKeyRole         string
+	DrivingKeyHashKeyValue string
+	LoadDate               time.Time
+	StartDate              time.Time
+}
+
+func (esatInsert *EffectivitySateliteInsertRecord) getDbTableName() string {
+	return definition.EffectivitySateliteTableName(esatInsert.SateliteName, esatInsert.Revision)
+}
+
+func (esatInsert *EffectivitySateliteInsertRecord) getLinkHashKeyDbColumnName() string {
+	return definition.HashKeyColumnName(esatInsert.LinkName)
+}
+
+func (esatInsert *EffectivitySateliteInsertRecord) getDrivingKeyDbColumnName() string {
+	return definition.RoleHashKeyColumnName(esatInsert.DrivingKeyRole, esatInsert.DrivingKeyHubName)
+}
+
+func (esatInsert *EffectivitySateliteInsertRecord) getStartDate() time.Time {
+	if esatInsert.StartDate.IsZero() {
+		return esatInsert.LoadDate
+	}
+
+	return esatInsert.StartDate
+}
+
+//FillHashKey compute link hash key and driving key hash key value from link references
+//if they are not provided; default hasher (MD5) is used if hasher is nil
+func (esatInsert *EffectivitySateliteInsertRecord) FillHashKey(hasher *hashkey.Hasher) error {
+	if esatInsert.LinkHashKeyValue != "" && esatInsert.DrivingKeyHashKeyValue != "" {
+		return nil
+	}
+
+	if esatInsert.LinkReferences == nil || len(esatInsert.LinkReferences) == 0 {
+		return fmt.Errorf("unable to compute hash key for effectivity satelite %s: "+
+			"no link reference found", esatInsert.SateliteName)
+	}
+
+	link := LinkInsertRecord{
+		LinkName:         esatInsert.LinkName,
+		ReferenceHashKey: make([]LinkReferenceInsertRecord, len(esatInsert.LinkReferences))}
+	copy(link.ReferenceHashKey, esatInsert.LinkReferences)
+	if linkErr := link.FillHashKey(hasher); linkErr != nil {
+		return linkErr
+	}
+
+	if esatInsert.LinkHashKeyValue == "" {
+		esatInsert.LinkHashKeyValue = link.HashKey
+	}
+
+	if esatInsert.DrivingKeyHashKeyValue == "" {
+		sameHubCount := 0
+		for _, ref := range link.ReferenceHashKey {
+			if strings.Compare(stringtool.ToSnakeCase(ref.HubName),
+				stringtool.ToSnakeCase(esatInsert.DrivingKeyHubName)) != 0 {
+				continue
+			}
+
+			if strings.Compare(ref.getHashKeyDbColumnName(), esatInsert.getDrivingKeyDbColumnName()) == 0 {
+				esatInsert.DrivingKeyHashKeyValue = ref.HashKeyValue
+				break
+			}
+			sameHubCount++
+		}
+
+		if esatInsert.DrivingKeyHashKeyValue == "" && sameHubCount > 1 {
+			return fmt.Errorf("unable to compute driving key hash key for effectivity satelite %s: "+
+				"link refer to hub %s more than once, driving key role is required",
+				esatInsert.SateliteName, esatInsert.DrivingKeyHubName)
+		}
+	}
+
+	if esatInsert.DrivingKeyHashKeyValue == "" {
+		return fmt.Errorf("unable to compute driving key hash key for effectivity satelite %s: "+
+			"no reference to hub %s found", esatInsert.SateliteName, esatInsert.getDrivingKeyDbColumnName())
+	}
+
+	return nil
+}
+
+//GenerateParamSQL to generate parameterized SQL statement to insert new effectivity satelite
+//record row; row is skipped if the same relationship (link hash key) is still open (end date is null)
+func (esatInsert *EffectivitySateliteInsertRecord) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	cols, args, err := esatInsert.prepareParamInsert()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlDialect = getDialect(sqlDialect)
+	selectSQL, selectErr := sqlDialect.SelectValuesSQL(cols)
+	if selectErr != nil {
+		return "", nil, selectErr
+	}
+
+	quote := sqlDialect.QuoteIdentifier
+	sql := fmt.Sprintf("INSERT INTO %s \n(%s) \n%s \n"+
+		"WHERE NOT EXISTS (SELECT 1 FROM %s AS cur WHERE cur.%s = ? AND cur.%s IS NULL)",
+		quote(esatInsert.getDbTableName()), dialect.QuoteIdentifiers(sqlDialect, getColumnNames(cols)),
+		selectSQL,
+		quote(esatInsert.getDbTableName()), quote(esatInsert.getLinkHashKeyDbColumnName()),
+		quote(definition.END_DATE))
+
+	args = append(args, esatInsert.LinkHashKeyValue)
+
+	return dialect.Rebind(sqlDialect, sql), args, nil
+}
+
+//GenerateEndDateParamSQL to generate parameterized SQL statement which close open relationship
+//of the same driving key with other partner (different link hash key) by setting its end date
+//to this record's start date
+func (esatInsert *EffectivitySateliteInsertRecord) GenerateEndDateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	if hashErr := esatInsert.FillHashKey(nil); hashErr != nil {
+		return "", nil, hashErr
+	}
+
+	sqlDialect = getDialect(sqlDialect)
+	quote := sqlDialect.QuoteIdentifier
+	sql := fmt.Sprintf("UPDATE %s SET %s = ? \nWHERE %s = ? AND %s <> ? AND %s IS NULL AND %s < ?",
+		quote(esatInsert.getDbTableName()),
+		quote(definition.END_DATE),
+		quote(esatInsert.getDrivingKeyDbColumnName()),
+		quote(esatInsert.getLinkHashKeyDbColumnName()),
+		quote(definition.END_DATE),
+		quote(definition.START_DATE))
+	args := []interface{}{
+		esatInsert.getStartDate(),
+		esatInsert.DrivingKeyHashKeyValue,
+		esatInsert.LinkHashKeyValue,
+		esatInsert.getStartDate()}
+
+	return dialect.Rebind(sqlDialect, sql), args, nil
+}
+
+//prepareParamInsert to generate insert column list and its ordered argument list
+func (esatInsert *EffectivitySateliteInsertRecord) prepareParamInsert() ([]rdbmstool.ColumnDefinition, []interface{}, error) {
+	if hashErr := esatInsert.FillHashKey(nil); hashErr != nil {
+		return nil, nil, hashErr
+	}
+
+	cols := []rdbmstool.ColumnDefinition{
+		createHashKeyColumn(esatInsert.getLinkHashKeyDbColumnName(), esatInsert.LinkHashKeyValue),
+		createHashKeyColumn(esatInsert.getDrivingKeyDbColumnName(), esatInsert.DrivingKeyHashKeyValue),
+		rdbmstool.ColumnDefinition{Name: definition.LOAD_DATE, DataType: rdbmstool.DATETIME},
+		rdbmstool.ColumnDefinition{Name: definition.START_DATE, DataType: rdbmstool.DATETIME},
+		rdbmstool.ColumnDefinition{Name: definition.RECORD_SOURCE,
+			DataType: rdbmstool.CHAR, Length: 100}}
+	args := []interface{}{
+		esatInsert.LinkHashKeyValue,
+		esatInsert.DrivingKeyHashKeyValue,
+		esatInsert.LoadDate,
+		esatInsert.getStartDate(),
+		esatInsert.RecordSource}
+
+	return cols, args, nil
+}
@@ -0,0 +1,86 @@
+package record
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guinso/datavault/dialect"
+)
+
+func TestEffectivitySateliteGenerateParamSQL(t *testing.T) {
+	esat := EffectivitySateliteInsertRecord{
+		SateliteName:      "InvoiceEmployee",
+		RecordSource:      "erp",
+		LinkName:          "InvoiceEmployee",
+		DrivingKeyHubName: "Invoice",
+		LoadDate:          time.Date(2017, 8, 3, 10, 0, 0, 0, time.UTC),
+		LinkReferences: []LinkReferenceInsertRecord{
+			LinkReferenceInsertRecord{HubName: "Invoice", HashKeyValue: "0123456789abcdef0123456789abcdef"},
+			LinkReferenceInsertRecord{HubName: "Employee",
+				BusinessKeyValues: []HubBusinessKeyInsertRecord{
+					HubBusinessKeyInsertRecord{BusinessKey: "EmployeeNo", BusinessValue: "E-01"}}}}}
+
+	endSQL, endArgs, endErr := esat.GenerateEndDateParamSQL(dialect.POSTGRES)
+	if endErr != nil {
+		t.Error(endErr.Error())
+		return
+	}
+
+	if esat.DrivingKeyHashKeyValue != "0123456789abcdef0123456789abcdef" || len(esat.LinkHashKeyValue) != 32 {
+		t.Errorf("Expect link and driving key hash key are filled, given %s and %s instead",
+			esat.LinkHashKeyValue, esat.DrivingKeyHashKeyValue)
+	}
+
+	if !strings.Contains(endSQL, "\"invoice_hash_key\" = $2 AND \"invoice_employee_hash_key\" <> $3") ||
+		len(endArgs) != 4 || endArgs[0] != esat.LoadDate {
+		t.Errorf("Expect other partner of driving key is closed at load date, given %s %v instead",
+			endSQL, endArgs)
+	}
+
+	sql, args, err := esat.GenerateParamSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !strings.HasSuffix(sql, "WHERE NOT EXISTS (SELECT 1 FROM \"esat_invoice_employee_rev0\" AS cur "+
+		"WHERE cur.\"invoice_employee_hash_key\" = $6 AND cur.\"end_date\" IS NULL)") || len(args) != 6 {
+		t.Errorf("Expect insert statement skip open relationship, given %s instead", sql)
+	}
+
+	noDriving := EffectivitySateliteInsertRecord{SateliteName: "InvoiceEmployee", LinkName: "InvoiceEmployee",
+		DrivingKeyHubName: "Customer", LinkReferences: esat.LinkReferences}
+	if hashErr := noDriving.FillHashKey(nil); hashErr == nil {
+		t.Error("Expect error for driving key which is not referred by link")
+	}
+}
+
+func TestEffectivitySateliteRoleDrivingKey(t *testing.T) {
+	esat := EffectivitySateliteInsertRecord{
+		SateliteName:      "EmployeeHierarchy",
+		RecordSource:      "hr",
+		LinkName:          "EmployeeHierarchy",
+		DrivingKeyHubName: "Employee",
+		LoadDate:          time.Date(2017, 8, 3, 10, 0, 0, 0, time.UTC),
+		LinkReferences: []LinkReferenceInsertRecord{
+			LinkReferenceInsertRecord{HubName: "Employee", Role: "Manager",
+				HashKeyValue: "0123456789abcdef0123456789abcdef"},
+			LinkReferenceInsertRecord{HubName: "Employee", Role: "Subordinate",
+				HashKeyValue: "fedcba9876543210fedcba9876543210"}}}
+
+	if hashErr := esat.FillHashKey(nil); hashErr == nil || !strings.Contains(hashErr.Error(), "role is required") {
+		t.Errorf("Expect error for ambiguous driving key, given %v instead", hashErr)
+	}
+
+	esat.DrivingKeyRole = "Subordinate"
+	endSQL, endArgs, endErr := esat.GenerateEndDateParamSQL(dialect.POSTGRES)
+	if endErr != nil {
+		t.Fatal(endErr.Error())
+	}
+
+	if !strings.Contains(endSQL, "WHERE \"subordinate_employee_hash_key\" = $2") ||
+		endArgs[1] != "fedcba9876543210fedcba9876543210" {
+		t.Errorf("Expect subordinate is driving key, given %s %v instead", endSQL, endArgs)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
 	"github.com/guinso/stringtool"
 )
 
@@ -26,35 +29,86 @@ type HubBusinessKeyInsertRecord struct {
 }
 
 func (hub *HubInsertRecord) getDbTableName() string {
-	return fmt.Sprintf("hub_%s_rev%d", stringtool.ToSnakeCase(hub.HubName), hub.HubRevision)
+	return definition.HubTableName(hub.HubName, hub.HubRevision)
 }
 
 func (hub *HubInsertRecord) getHashKeyDbColumnName() string {
-	return fmt.Sprintf("%s_hash_key", stringtool.ToSnakeCase(hub.HubName))
+	return definition.HashKeyColumnName(hub.HubName)
+}
+
+//FillHashKey compute hub hash key from business key values if hash key is not provided;
+//default hasher (MD5) is used if hasher is nil
+func (hub *HubInsertRecord) FillHashKey(hasher *hashkey.Hasher) error {
+	if hub.HashKey != "" {
+		return nil
+	}
+
+	if hub.BusinessKeyVues == nil || len(hub.BusinessKeyVues) == 0 {
+		return fmt.Errorf("unable to compute hash key for hub %s: no business key value found",
+			hub.HubName)
+	}
+
+	hub.HashKey = computeBusinessHashKey(hasher, hub.BusinessKeyVues)
+
+	return nil
 }
 
 //GenerateSQL to generate SQL insert statement for hub record
 func (hub *HubInsertRecord) GenerateSQL() (string, error) {
+	cols, args, err := hub.prepareInsert()
+	if err != nil {
+		return "", err
+	}
+
+	return generateLiteralInsertSQL(hub.getDbTableName(), cols, args)
+}
+
+//GenerateParamSQL to generate parameterized SQL insert statement for hub record;
+//return statement with dialect's placeholder and its ordered argument list
+func (hub *HubInsertRecord) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	cols, args, err := hub.prepareInsert()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return generateParamInsertSQL(getDialect(sqlDialect), hub.getDbTableName(),
+		getColumnNames(cols)), args, nil
+}
+
+//GenerateIdempotentParamSQL to generate parameterized SQL insert statement for hub record
+//which skip insert if hub hash key already exists, so re-loading same record is safe
+func (hub *HubInsertRecord) GenerateIdempotentParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	sql, args, err := hub.GenerateParamSQL(sqlDialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return getDialect(sqlDialect).InsertIgnoreSQL(sql, []string{hub.getHashKeyDbColumnName()}), args, nil
+}
+
+//prepareInsert to generate insert column list and its ordered value list
+func (hub *HubInsertRecord) prepareInsert() ([]rdbmstool.ColumnDefinition, []interface{}, error) {
 	if hub.BusinessKeyVues == nil || len(hub.BusinessKeyVues) == 0 {
-		return "", errors.New("hub must has atlest one business key value")
+		return nil, nil, errors.New("hub must has atlest one business key value")
 	}
 
-	colSQL := fmt.Sprintf("`%s`, `%s`, `%s`",
-		hub.getHashKeyDbColumnName(),
-		definition.LOAD_DATE,
-		definition.RECORD_SOURCE)
+	if hashErr := hub.FillHashKey(nil); hashErr != nil {
+		return nil, nil, hashErr
+	}
 
-	valueSQL := fmt.Sprintf("'%s', '%s', '%s'",
-		hub.HashKey,
-		hub.LoadDate.Format("2006-01-02"),
-		hub.RecordSource)
+	cols := []rdbmstool.ColumnDefinition{
+		createHashKeyColumn(hub.getHashKeyDbColumnName(), hub.HashKey),
+		rdbmstool.ColumnDefinition{Name: definition.LOAD_DATE, DataType: rdbmstool.DATETIME},
+		rdbmstool.ColumnDefinition{Name: definition.RECORD_SOURCE,
+			DataType: rdbmstool.CHAR, Length: 100}}
+	args := []interface{}{hub.HashKey, hub.LoadDate, hub.RecordSource}
 
 	for _, business := range hub.BusinessKeyVues {
-		colSQL = colSQL + ", `" + stringtool.ToSnakeCase(business.BusinessKey) + "`"
-
-		valueSQL = valueSQL + ", '" + business.BusinessValue + "'"
+		cols = append(cols, rdbmstool.ColumnDefinition{
+			Name:     stringtool.ToSnakeCase(business.BusinessKey),
+			DataType: rdbmstool.CHAR, Length: 100})
+		args = append(args, business.BusinessValue)
 	}
 
-	return fmt.Sprintf("INSERT INTO `%s` \n(%s) \nVALUES (%s)",
-		hub.getDbTableName(), colSQL, valueSQL), nil
+	return cols, args, nil
 }
@@ -0,0 +1,146 @@
+package record
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guinso/datavault/dialect"
+)
+
+func TestHubGenerateParamSQL(t *testing.T) {
+	hub := HubInsertRecord{
+		HubName:      "Customer",
+		HubRevision:  0,
+		RecordSource: "crm",
+		LoadDate:     time.Date(2017, 8, 3, 10, 0, 0, 0, time.UTC),
+		HashKey:      "0123456789abcdef0123456789abcdef",
+		BusinessKeyVues: []HubBusinessKeyInsertRecord{
+			HubBusinessKeyInsertRecord{
+				BusinessKey:   "Name",
+				BusinessValue: "O'Brien"}}}
+
+	sql, args, err := hub.GenerateParamSQL(dialect.MYSQL)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if strings.Contains(sql, "O'Brien") {
+		t.Errorf("Business key value should not be embedded into SQL statement: %s", sql)
+	}
+
+	if strings.Count(sql, "?") != len(args) {
+		t.Errorf("Expect %d placeholders, given %d instead", len(args), strings.Count(sql, "?"))
+	}
+
+	if len(args) != 4 || args[3] != "O'Brien" {
+		t.Errorf("Expect last argument is %s, given %v instead", "O'Brien", args)
+	}
+}
+
+func TestHubFillHashKey(t *testing.T) {
+	hubA := HubInsertRecord{
+		HubName: "Invoice",
+		BusinessKeyVues: []HubBusinessKeyInsertRecord{
+			HubBusinessKeyInsertRecord{BusinessKey: "InvoiceNo", BusinessValue: "inv-001"},
+			HubBusinessKeyInsertRecord{BusinessKey: "Branch", BusinessValue: "KL "}}}
+	hubB := HubInsertRecord{
+		HubName: "Invoice",
+		BusinessKeyVues: []HubBusinessKeyInsertRecord{
+			HubBusinessKeyInsertRecord{BusinessKey: "Branch", BusinessValue: "kl"},
+			HubBusinessKeyInsertRecord{BusinessKey: "InvoiceNo", BusinessValue: "INV-001"}}}
+
+	if err := hubA.FillHashKey(nil); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if err := hubB.FillHashKey(nil); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if len(hubA.HashKey) != 32 {
+		t.Errorf("Expect hash key length is 32, given %d instead", len(hubA.HashKey))
+	}
+
+	if strings.Compare(hubA.HashKey, hubB.HashKey) != 0 {
+		t.Errorf("Expect same hash key for same business keys, given %s and %s instead",
+			hubA.HashKey, hubB.HashKey)
+	}
+
+	link := LinkInsertRecord{
+		LinkName: "InvoiceCustomer",
+		ReferenceHashKey: []LinkReferenceInsertRecord{
+			LinkReferenceInsertRecord{HubName: "Invoice", HashKeyValue: hubA.HashKey},
+			LinkReferenceInsertRecord{HubName: "Customer",
+				BusinessKeyValues: []HubBusinessKeyInsertRecord{
+					HubBusinessKeyInsertRecord{BusinessKey: "Name", BusinessValue: "O'Brien"}}}}}
+	if err := link.FillHashKey(nil); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if link.HashKey == "" || link.ReferenceHashKey[1].HashKeyValue == "" {
+		t.Error("Expect link and hub reference hash key are filled")
+	}
+}
+
+func TestHubGenerateIdempotentParamSQL(t *testing.T) {
+	hub := HubInsertRecord{
+		HubName: "Invoice",
+		BusinessKeyVues: []HubBusinessKeyInsertRecord{
+			HubBusinessKeyInsertRecord{BusinessKey: "InvoiceNo", BusinessValue: "INV-001"}}}
+
+	sql, _, err := hub.GenerateIdempotentParamSQL(dialect.MYSQL)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !strings.HasSuffix(sql, "ON DUPLICATE KEY UPDATE `invoice_hash_key` = `invoice_hash_key`") {
+		t.Errorf("Expect insert statement skip existing hash key, given %s instead", sql)
+	}
+}
+
+func TestLinkRoleReference(t *testing.T) {
+	employee := []HubBusinessKeyInsertRecord{
+		HubBusinessKeyInsertRecord{BusinessKey: "EmployeeNo", BusinessValue: "E-01"}}
+	manager := []HubBusinessKeyInsertRecord{
+		HubBusinessKeyInsertRecord{BusinessKey: "EmployeeNo", BusinessValue: "E-02"}}
+
+	link := LinkInsertRecord{
+		LinkName: "EmployeeManager",
+		ReferenceHashKey: []LinkReferenceInsertRecord{
+			LinkReferenceInsertRecord{HubName: "Employee", BusinessKeyValues: employee},
+			LinkReferenceInsertRecord{HubName: "Employee", Role: "Manager", BusinessKeyValues: manager}}}
+
+	sql, _, err := link.GenerateParamSQL(dialect.MYSQL)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if !strings.Contains(sql, "`employee_hash_key`, `manager_employee_hash_key`") {
+		t.Errorf("Expect role-named reference column, given %s instead", sql)
+	}
+
+	reversed := LinkInsertRecord{
+		LinkName: "EmployeeManager",
+		ReferenceHashKey: []LinkReferenceInsertRecord{
+			LinkReferenceInsertRecord{HubName: "Employee", Role: "Manager", BusinessKeyValues: employee},
+			LinkReferenceInsertRecord{HubName: "Employee", BusinessKeyValues: manager}}}
+	if hashErr := reversed.FillHashKey(nil); hashErr != nil {
+		t.Error(hashErr.Error())
+		return
+	}
+
+	if strings.Compare(link.HashKey, reversed.HashKey) == 0 {
+		t.Error("Expect different hash key when employee and manager role are swapped")
+	}
+
+	link.ReferenceHashKey[1].Role = ""
+	if _, _, dupErr := link.GenerateParamSQL(dialect.MYSQL); dupErr == nil {
+		t.Error("Expect error for duplicate reference column")
+	}
+}
@@ -0,0 +1,20 @@
+package record
+
+import (
+	"fmt"
+	"strings"
+)
+
+//IntegrityError is list of integrity violation(s) found in data vault insert record
+type IntegrityError struct {
+	Violations []string
+}
+
+func (integrityErr *IntegrityError) Error() string {
+	return fmt.Sprintf("%d integrity violation(s) found:\n- %s",
+		len(integrityErr.Violations), strings.Join(integrityErr.Violations, "\n- "))
+}
+
+func (integrityErr *IntegrityError) add(format string, args ...interface{}) {
+	integrityErr.Violations = append(integrityErr.Violations, fmt.Sprintf(format, args...))
+}
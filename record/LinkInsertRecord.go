@@ -3,10 +3,13 @@ package record
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/guinso/datavault/definition"
-	"github.com/guinso/stringtool"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
 )
 
 //LinkInsertRecord is link insert record schema
@@ -19,38 +22,127 @@ type LinkInsertRecord struct {
 	RecordSource     string
 }
 
-//LinkReferenceInsertRecord is link's hub reference insert record schema
+//LinkReferenceInsertRecord is link's hub reference insert record schema;
+//Role is required if link refer to the same hub more than once (must match link definition);
+//BusinessKeyValues is optional, used to compute hash key value if it is not provided
 type LinkReferenceInsertRecord struct {
-	HubName      string
-	HashKeyValue string
+	HubName           string
+	Role              string
+	HashKeyValue      string
+	BusinessKeyValues []HubBusinessKeyInsertRecord
 }
 
 func (link *LinkInsertRecord) getDbTableName() string {
-	return fmt.Sprintf("link_%s_rev%d", stringtool.ToSnakeCase(link.LinkName), link.LinkRevision)
+	return definition.LinkTableName(link.LinkName, link.LinkRevision)
 }
 
 func (link *LinkInsertRecord) getHashKeyDbColumnName() string {
-	return fmt.Sprintf("%s_hash_key", stringtool.ToSnakeCase(link.LinkName))
+	return definition.HashKeyColumnName(link.LinkName)
+}
+
+func (ref *LinkReferenceInsertRecord) getHashKeyDbColumnName() string {
+	return definition.RoleHashKeyColumnName(ref.Role, ref.HubName)
+}
+
+//FillHashKey compute missing hub reference hash key(s) from their business key values,
+//then compute link hash key from referenced hub hash keys if it is not provided;
+//default hasher (MD5) is used if hasher is nil
+func (link *LinkInsertRecord) FillHashKey(hasher *hashkey.Hasher) error {
+	for index := range link.ReferenceHashKey {
+		ref := &link.ReferenceHashKey[index]
+		if ref.HashKeyValue != "" {
+			continue
+		}
+
+		if ref.BusinessKeyValues == nil || len(ref.BusinessKeyValues) == 0 {
+			return fmt.Errorf("unable to compute hash key of hub %s for link %s: "+
+				"no hash key or business key value found", ref.HubName, link.LinkName)
+		}
+
+		ref.HashKeyValue = computeBusinessHashKey(hasher, ref.BusinessKeyValues)
+	}
+
+	if link.HashKey != "" {
+		return nil
+	}
+
+	//order by hash key column name so caller's ordering does not affect result
+	refs := make([]LinkReferenceInsertRecord, len(link.ReferenceHashKey))
+	copy(refs, link.ReferenceHashKey)
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].getHashKeyDbColumnName() < refs[j].getHashKeyDbColumnName()
+	})
+
+	values := make([]string, len(refs))
+	for index, ref := range refs {
+		values[index] = ref.HashKeyValue
+	}
+	link.HashKey = getHasher(hasher).HashKey(values...)
+
+	return nil
 }
 
 //GenerateSQL is to generate SQL insert statement for link schema
 func (link *LinkInsertRecord) GenerateSQL() (string, error) {
+	cols, args, err := link.prepareInsert()
+	if err != nil {
+		return "", err
+	}
+
+	return generateLiteralInsertSQL(link.getDbTableName(), cols, args)
+}
+
+//GenerateParamSQL is to generate parameterized SQL insert statement for link schema;
+//return statement with dialect's placeholder and its ordered argument list
+func (link *LinkInsertRecord) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	cols, args, err := link.prepareInsert()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return generateParamInsertSQL(getDialect(sqlDialect), link.getDbTableName(),
+		getColumnNames(cols)), args, nil
+}
+
+//GenerateIdempotentParamSQL to generate parameterized SQL insert statement for link record
+//which skip insert if link hash key already exists, so re-loading same record is safe
+func (link *LinkInsertRecord) GenerateIdempotentParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	sql, args, err := link.GenerateParamSQL(sqlDialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return getDialect(sqlDialect).InsertIgnoreSQL(sql, []string{link.getHashKeyDbColumnName()}), args, nil
+}
+
+//prepareInsert to generate insert column list and its ordered value list
+func (link *LinkInsertRecord) prepareInsert() ([]rdbmstool.ColumnDefinition, []interface{}, error) {
 	if link.ReferenceHashKey == nil || len(link.ReferenceHashKey) < 2 {
-		return "", errors.New("Link must has atleast two reference hub")
+		return nil, nil, errors.New("Link must has atleast two reference hub")
 	}
 
-	colSQL := fmt.Sprintf("`%s`, `%s`, `%s`",
-		link.getHashKeyDbColumnName(),
-		definition.RECORD_SOURCE,
-		definition.LOAD_DATE)
+	if hashErr := link.FillHashKey(nil); hashErr != nil {
+		return nil, nil, hashErr
+	}
 
-	valueSQL := fmt.Sprintf("'%s', '%s', '%s'", link.HashKey, link.RecordSource, link.LoadDate)
+	cols := []rdbmstool.ColumnDefinition{
+		createHashKeyColumn(link.getHashKeyDbColumnName(), link.HashKey),
+		rdbmstool.ColumnDefinition{Name: definition.RECORD_SOURCE,
+			DataType: rdbmstool.CHAR, Length: 100},
+		rdbmstool.ColumnDefinition{Name: definition.LOAD_DATE, DataType: rdbmstool.DATETIME}}
+	args := []interface{}{link.HashKey, link.RecordSource, link.LoadDate}
 
+	refColumns := make(map[string]bool)
 	for _, ref := range link.ReferenceHashKey {
-		colSQL = colSQL + ", `" + stringtool.ToSnakeCase(ref.HubName) + "_hash_key`"
-		valueSQL = valueSQL + ", '" + ref.HashKeyValue + "'"
+		if refColumns[ref.getHashKeyDbColumnName()] {
+			return nil, nil, fmt.Errorf("Link %s has duplicate reference column %s, "+
+				"set distinct role for each reference to the same hub", link.LinkName, ref.getHashKeyDbColumnName())
+		}
+		refColumns[ref.getHashKeyDbColumnName()] = true
+
+		cols = append(cols, createHashKeyColumn(ref.getHashKeyDbColumnName(), ref.HashKeyValue))
+		args = append(args, ref.HashKeyValue)
 	}
 
-	return fmt.Sprintf("INSERT INTO `%s` \n(%s) \nVALUES (%s)",
-		link.getDbTableName(), colSQL, valueSQL), nil
+	return cols, args, nil
 }
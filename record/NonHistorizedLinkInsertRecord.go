@@ -0,0 +1,166 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//NonHistorizedLinkInsertRecord is non-historized link insert record schema;
+//DependentKeys is attribute name(s) which identify transaction together with hub references,
+//e.g. payment number, their values are included into link hash key computation
+type NonHistorizedLinkInsertRecord struct {
+	LinkName         string
+	LinkRevision     int
+	HashKey          string
+	LoadDate         time.Time
+	RecordSource     string
+	ReferenceHashKey []LinkReferenceInsertRecord
+	DependentKeys    []string
+	Attributes       []SateliteAttrInsertRecord
+}
+
+func (nhl *NonHistorizedLinkInsertRecord) getDbTableName() string {
+	return definition.NonHistorizedLinkTableName(nhl.LinkName, nhl.LinkRevision)
+}
+
+func (nhl *NonHistorizedLinkInsertRecord) getHashKeyDbColumnName() string {
+	return definition.HashKeyColumnName(nhl.LinkName)
+}
+
+//FillHashKey compute missing hub reference hash key(s) from their business key values,
+//then compute link hash key from referenced hub hash keys and dependent key values
+//if it is not provided; default hasher (MD5) is used if hasher is nil
+func (nhl *NonHistorizedLinkInsertRecord) FillHashKey(hasher *hashkey.Hasher) error {
+	//fill hub reference hash key(s) in place
+	link := LinkInsertRecord{LinkName: nhl.LinkName, ReferenceHashKey: nhl.ReferenceHashKey}
+	if linkErr := link.FillHashKey(hasher); linkErr != nil {
+		return linkErr
+	}
+
+	if nhl.HashKey != "" {
+		return nil
+	}
+
+	//order by column name so caller's ordering does not affect result
+	refs := make([]LinkReferenceInsertRecord, len(nhl.ReferenceHashKey))
+	copy(refs, nhl.ReferenceHashKey)
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].getHashKeyDbColumnName() < refs[j].getHashKeyDbColumnName()
+	})
+
+	keys := make([]string, len(nhl.DependentKeys))
+	for index, key := range nhl.DependentKeys {
+		keys[index] = stringtool.ToSnakeCase(key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(refs)+len(keys))
+	for _, ref := range refs {
+		values = append(values, ref.HashKeyValue)
+	}
+
+	for _, key := range keys {
+		var keyAttr *SateliteAttrInsertRecord
+		for index := range nhl.Attributes {
+			if stringtool.ToSnakeCase(nhl.Attributes[index].AttributeName) == key {
+				keyAttr = &nhl.Attributes[index]
+				break
+			}
+		}
+
+		if keyAttr == nil || keyAttr.Value == nil {
+			return fmt.Errorf("unable to compute hash key for non-historized link %s: "+
+				"no value for dependent key %s", nhl.LinkName, key)
+		}
+
+		keyArg, keyErr := keyAttr.convertValueToArg()
+		if keyErr != nil {
+			return keyErr
+		}
+
+		values = append(values, formatHashInput(keyArg))
+	}
+
+	nhl.HashKey = getHasher(hasher).HashKey(values...)
+
+	return nil
+}
+
+//GenerateSQL is to generate SQL insert statement for non-historized link record
+func (nhl *NonHistorizedLinkInsertRecord) GenerateSQL() (string, error) {
+	cols, args, err := nhl.prepareInsert()
+	if err != nil {
+		return "", err
+	}
+
+	return generateLiteralInsertSQL(nhl.getDbTableName(), cols, args)
+}
+
+//GenerateParamSQL is to generate parameterized SQL insert statement for non-historized link record;
+//return statement with dialect's placeholder and its ordered argument list
+func (nhl *NonHistorizedLinkInsertRecord) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	cols, args, err := nhl.prepareInsert()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return generateParamInsertSQL(getDialect(sqlDialect), nhl.getDbTableName(),
+		getColumnNames(cols)), args, nil
+}
+
+//GenerateIdempotentParamSQL to generate parameterized SQL insert statement for non-historized
+//link record which skip insert if link hash key already exists, so re-loading same record is safe
+func (nhl *NonHistorizedLinkInsertRecord) GenerateIdempotentParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	sql, args, err := nhl.GenerateParamSQL(sqlDialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return getDialect(sqlDialect).InsertIgnoreSQL(sql, []string{nhl.getHashKeyDbColumnName()}), args, nil
+}
+
+//prepareInsert to generate insert column list and its ordered value list
+func (nhl *NonHistorizedLinkInsertRecord) prepareInsert() ([]rdbmstool.ColumnDefinition, []interface{}, error) {
+	if nhl.ReferenceHashKey == nil || len(nhl.ReferenceHashKey) < 2 {
+		return nil, nil, errors.New("Non-historized link must has atleast two reference hub")
+	}
+
+	if hashErr := nhl.FillHashKey(nil); hashErr != nil {
+		return nil, nil, hashErr
+	}
+
+	cols := []rdbmstool.ColumnDefinition{
+		createHashKeyColumn(nhl.getHashKeyDbColumnName(), nhl.HashKey),
+		rdbmstool.ColumnDefinition{Name: definition.RECORD_SOURCE,
+			DataType: rdbmstool.CHAR, Length: 100},
+		rdbmstool.ColumnDefinition{Name: definition.LOAD_DATE, DataType: rdbmstool.DATETIME}}
+	args := []interface{}{nhl.HashKey, nhl.RecordSource, nhl.LoadDate}
+
+	refColumns := make(map[string]bool)
+	for _, ref := range nhl.ReferenceHashKey {
+		if refColumns[ref.getHashKeyDbColumnName()] {
+			return nil, nil, fmt.Errorf("Non-historized link %s has duplicate reference column %s, "+
+				"set distinct role for each reference to the same hub", nhl.LinkName, ref.getHashKeyDbColumnName())
+		}
+		refColumns[ref.getHashKeyDbColumnName()] = true
+
+		cols = append(cols, createHashKeyColumn(ref.getHashKeyDbColumnName(), ref.HashKeyValue))
+		args = append(args, ref.HashKeyValue)
+	}
+
+	attrCols, attrArgs, attrErr := prepareAttributeInsert(nhl.Attributes)
+	if attrErr != nil {
+		return nil, nil, fmt.Errorf(
+			"NonHistorizedLinkInsertRecord Fail to generate SQL: \n%s", attrErr.Error())
+	}
+
+	return append(cols, attrCols...), append(args, attrArgs...), nil
+}
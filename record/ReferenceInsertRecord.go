@@ -0,0 +1,105 @@
+package record
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//ReferenceInsertRecord is reference table insert record schema;
+//Keys is attribute name(s) of reference primary key, e.g. currency code
+type ReferenceInsertRecord struct {
+	ReferenceName string
+	Revision      int
+	RecordSource  string
+	LoadDate      time.Time
+	Keys          []string
+	Attributes    []SateliteAttrInsertRecord
+}
+
+func (refInsert *ReferenceInsertRecord) getDbTableName() string {
+	return definition.ReferenceTableName(refInsert.ReferenceName, refInsert.Revision)
+}
+
+func (refInsert *ReferenceInsertRecord) getKeyDbColumnNames() []string {
+	keys := make([]string, len(refInsert.Keys))
+	for index, key := range refInsert.Keys {
+		keys[index] = stringtool.ToSnakeCase(key)
+	}
+
+	return keys
+}
+
+//GenerateSQL to generate SQL insert statement for reference record
+func (refInsert *ReferenceInsertRecord) GenerateSQL() (string, error) {
+	cols, args, err := refInsert.prepareInsert()
+	if err != nil {
+		return "", err
+	}
+
+	return generateLiteralInsertSQL(refInsert.getDbTableName(), cols, args)
+}
+
+//GenerateParamSQL to generate parameterized SQL insert statement for reference record;
+//return statement with dialect's placeholder and its ordered argument list
+func (refInsert *ReferenceInsertRecord) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	cols, args, err := refInsert.prepareInsert()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return generateParamInsertSQL(getDialect(sqlDialect), refInsert.getDbTableName(),
+		getColumnNames(cols)), args, nil
+}
+
+//GenerateIdempotentParamSQL to generate parameterized SQL insert statement for reference record
+//which skip insert if reference key already exists, so re-loading same record is safe
+func (refInsert *ReferenceInsertRecord) GenerateIdempotentParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	sql, args, err := refInsert.GenerateParamSQL(sqlDialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return getDialect(sqlDialect).InsertIgnoreSQL(sql, refInsert.getKeyDbColumnNames()), args, nil
+}
+
+//prepareInsert to generate insert column list and its ordered value list
+func (refInsert *ReferenceInsertRecord) prepareInsert() ([]rdbmstool.ColumnDefinition, []interface{}, error) {
+	if refInsert.Keys == nil || len(refInsert.Keys) == 0 {
+		return nil, nil, errors.New("Reference must has atleast one key")
+	}
+
+	cols := []rdbmstool.ColumnDefinition{
+		rdbmstool.ColumnDefinition{Name: definition.LOAD_DATE, DataType: rdbmstool.DATETIME},
+		rdbmstool.ColumnDefinition{Name: definition.RECORD_SOURCE,
+			DataType: rdbmstool.CHAR, Length: 100}}
+	args := []interface{}{refInsert.LoadDate, refInsert.RecordSource}
+
+	attrCols, attrArgs, attrErr := prepareAttributeInsert(refInsert.Attributes)
+	if attrErr != nil {
+		return nil, nil, fmt.Errorf(
+			"ReferenceInsertRecord Fail to generate SQL: \n%s", attrErr.Error())
+	}
+
+	//every key must has value
+	for _, key := range refInsert.getKeyDbColumnNames() {
+		found := false
+		for index, col := range attrCols {
+			if col.Name == key && attrArgs[index] != nil {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, nil, fmt.Errorf("Reference %s has no value for key %s", refInsert.ReferenceName, key)
+		}
+	}
+
+	return append(cols, attrCols...), append(args, attrArgs...), nil
+}
@@ -4,23 +4,40 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/datavault/hashkey"
 	"github.com/guinso/rdbmstool"
 	"github.com/guinso/stringtool"
 )
 
-//SateliteInsertRecord is satelite insert record schema
+//SateliteInsertRecord is satelite insert record schema;
+//HubBusinessKeyValues is optional, used to compute hub hash key value if it is not provided;
+//satelite attached to link set LinkName instead of HubName, while LinkReferences is optional,
+//used to compute link hash key value if it is not provided;
+//MultiActiveKeys is attribute name(s) of multi-active satelite key, each key has its own active row
 type SateliteInsertRecord struct {
-	SateliteName    string
-	Revision        int
-	RecordSource    string
-	HubName         string
-	HubHashKeyValue string
-	LoadDate        time.Time
-	Attributes      []SateliteAttrInsertRecord
+	SateliteName         string
+	Revision             int
+	RecordSource         string
+	HubName              string
+	HubHashKeyValue      string
+	HubBusinessKeyValues []HubBusinessKeyInsertRecord
+	LinkName             string
+	LinkHashKeyValue     string
+	LinkReferences       []LinkReferenceInsertRecord
+	LoadDate             time.Time
+	Attributes           []SateliteAttrInsertRecord
+	MultiActiveKeys      []string
+
+	//HasHashDiff indicate satelite has hash diff column; HashDiff is computed
+	//from attribute values if it is not provided
+	HasHashDiff bool
+	HashDiff    string
 }
 
 //SateliteAttrInsertRecord is satelite attribute insert record schema
@@ -31,101 +48,320 @@ type SateliteAttrInsertRecord struct {
 }
 
 func (satInsert *SateliteInsertRecord) getDbTableName() string {
-	return fmt.Sprintf("sat_%s_rev%d",
-		stringtool.ToSnakeCase(satInsert.SateliteName), satInsert.Revision)
+	return definition.SateliteTableName(satInsert.SateliteName, satInsert.Revision)
+}
+
+func (satInsert *SateliteInsertRecord) isLinkSatelite() bool {
+	return satInsert.LinkName != ""
+}
+
+//getHashKeyDbColumnName is hash key column name of referred hub or link
+func (satInsert *SateliteInsertRecord) getHashKeyDbColumnName() string {
+	if satInsert.isLinkSatelite() {
+		return definition.HashKeyColumnName(satInsert.LinkName)
+	}
+
+	return definition.HashKeyColumnName(satInsert.HubName)
 }
 
-func (satInsert *SateliteInsertRecord) getHubColumnName() string {
-	return fmt.Sprintf("%s_hash_key",
-		stringtool.ToSnakeCase(satInsert.HubName))
+//getHashKeyValue is hash key value of referred hub or link
+func (satInsert *SateliteInsertRecord) getHashKeyValue() string {
+	if satInsert.isLinkSatelite() {
+		return satInsert.LinkHashKeyValue
+	}
+
+	return satInsert.HubHashKeyValue
+}
+
+//FillHashKey compute hub hash key value from hub business key values (or link hash key
+//value from link references) if it is not provided, and hash diff from attribute values
+//if satelite has hash diff column; default hasher (MD5) is used if hasher is nil
+func (satInsert *SateliteInsertRecord) FillHashKey(hasher *hashkey.Hasher) error {
+	if satInsert.HasHashDiff && satInsert.HashDiff == "" {
+		hashDiff, diffErr := satInsert.computeHashDiff(hasher)
+		if diffErr != nil {
+			return diffErr
+		}
+
+		satInsert.HashDiff = hashDiff
+	}
+
+	if satInsert.isLinkSatelite() {
+		if satInsert.LinkHashKeyValue != "" {
+			return nil
+		}
+
+		if satInsert.LinkReferences == nil || len(satInsert.LinkReferences) == 0 {
+			return fmt.Errorf("unable to compute link hash key for satelite %s: "+
+				"no link reference found", satInsert.SateliteName)
+		}
+
+		link := LinkInsertRecord{
+			LinkName:         satInsert.LinkName,
+			ReferenceHashKey: make([]LinkReferenceInsertRecord, len(satInsert.LinkReferences))}
+		copy(link.ReferenceHashKey, satInsert.LinkReferences)
+		if linkErr := link.FillHashKey(hasher); linkErr != nil {
+			return linkErr
+		}
+
+		satInsert.LinkHashKeyValue = link.HashKey
+
+		return nil
+	}
+
+	if satInsert.HubHashKeyValue != "" {
+		return nil
+	}
+
+	if satInsert.HubBusinessKeyValues == nil || len(satInsert.HubBusinessKeyValues) == 0 {
+		return fmt.Errorf("unable to compute hub hash key for satelite %s: "+
+			"no business key value found", satInsert.SateliteName)
+	}
+
+	satInsert.HubHashKeyValue = computeBusinessHashKey(hasher, satInsert.HubBusinessKeyValues)
+
+	return nil
+}
+
+//computeHashDiff compute hash diff from attribute values; values are ordered by
+//attribute column name so caller's ordering does not affect result, and hashed as it is
+//(without business key normalisation) so case or whitespace change is detected
+func (satInsert *SateliteInsertRecord) computeHashDiff(hasher *hashkey.Hasher) (string, error) {
+	attrs := make([]SateliteAttrInsertRecord, len(satInsert.Attributes))
+	copy(attrs, satInsert.Attributes)
+	sort.SliceStable(attrs, func(i, j int) bool {
+		return stringtool.ToSnakeCase(attrs[i].AttributeName) <
+			stringtool.ToSnakeCase(attrs[j].AttributeName)
+	})
+
+	//null value is kept as nil, so it is hashed differently from empty string
+	values := make([]*string, len(attrs))
+	for index, attrValue := range attrs {
+		tmpArg, tmpErr := attrValue.convertValueToArg()
+		if tmpErr != nil {
+			return "", fmt.Errorf("unable to compute hash diff for satelite %s: %s",
+				satInsert.SateliteName, tmpErr.Error())
+		}
+
+		if tmpArg != nil {
+			tmpValue := formatHashInput(tmpArg)
+			values[index] = &tmpValue
+		}
+	}
+
+	return getHasher(hasher).HashDiff(values...), nil
 }
 
 //GenerateSQL to generate executable SQL statement to insert new satelite record row
 func (satInsert *SateliteInsertRecord) GenerateSQL() (string, error) {
-	var columns string
-	var values string
+	cols, args, err := satInsert.prepareParamInsert()
+	if err != nil {
+		return "", err
+	}
 
-	if satInsert.Attributes == nil || len(satInsert.Attributes) == 0 {
-		return "", errors.New(
-			"unable to generate SQL to insert new satelite record as there is no attribute found")
+	return generateLiteralInsertSQL(satInsert.getDbTableName(), cols, args)
+}
+
+//GenerateParamSQL to generate parameterized SQL statement to insert new satelite record row;
+//return statement with dialect's placeholder and its ordered argument list
+func (satInsert *SateliteInsertRecord) GenerateParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	cols, args, err := satInsert.prepareParamInsert()
+	if err != nil {
+		return "", nil, err
 	}
 
-	for index, attrValue := range satInsert.Attributes {
-		tmpStr, tmpErr := attrValue.convertValueToString()
+	return generateParamInsertSQL(getDialect(sqlDialect), satInsert.getDbTableName(),
+		getColumnNames(cols)), args, nil
+}
 
-		if tmpErr != nil {
-			return "", fmt.Errorf(
-				"SateliteInsertRecord Fail to generate SQL: \n%s", tmpErr.Error())
+//GenerateChangedParamSQL to generate parameterized SQL statement which insert new satelite
+//record row only if its hash diff is different from current (latest) row of the same hub hash key;
+//satelite must has hash diff column
+func (satInsert *SateliteInsertRecord) GenerateChangedParamSQL(sqlDialect dialect.Dialect) (string, []interface{}, error) {
+	if !satInsert.HasHashDiff {
+		return "", nil, fmt.Errorf(
+			"satelite %s has no hash diff column to detect changes", satInsert.SateliteName)
+	}
+
+	cols, args, err := satInsert.prepareParamInsert()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlDialect = getDialect(sqlDialect)
+	selectSQL, selectErr := sqlDialect.SelectValuesSQL(cols)
+	if selectErr != nil {
+		return "", nil, selectErr
+	}
+
+	curConditions, keyArgs, keyErr := satInsert.getMultiActiveConditions(sqlDialect, "cur.")
+	if keyErr != nil {
+		return "", nil, keyErr
+	}
+	latestConditions, _, _ := satInsert.getMultiActiveConditions(sqlDialect, "latest.")
+
+	quote := sqlDialect.QuoteIdentifier
+	sql := fmt.Sprintf("INSERT INTO %s \n(%s) \n%s \n"+
+		"WHERE NOT EXISTS (SELECT 1 FROM %s AS cur WHERE cur.%s = ? AND cur.%s = ?%s "+
+		"AND cur.%s = (SELECT MAX(latest.%s) FROM %s AS latest WHERE latest.%s = ?%s))",
+		quote(satInsert.getDbTableName()), dialect.QuoteIdentifiers(sqlDialect, getColumnNames(cols)),
+		selectSQL,
+		quote(satInsert.getDbTableName()), quote(satInsert.getHashKeyDbColumnName()),
+		quote(definition.HASH_DIFF), curConditions, quote(definition.LOAD_DATE), quote(definition.LOAD_DATE),
+		quote(satInsert.getDbTableName()), quote(satInsert.getHashKeyDbColumnName()), latestConditions)
+
+	args = append(args, satInsert.getHashKeyValue(), satInsert.HashDiff)
+	args = append(args, keyArgs...)
+	args = append(args, satInsert.getHashKeyValue())
+	args = append(args, keyArgs...)
+
+	return dialect.Rebind(sqlDialect, sql), args, nil
+}
+
+//GenerateEndDateParamSQL to generate parameterized SQL statement which close previous open
+//row (end date is null) of the same hub (or link) hash key (and multi-active key) by setting its
+//end date to this record's load date; if onlyIfChanged, open row with identical hash diff is left open
+func (satInsert *SateliteInsertRecord) GenerateEndDateParamSQL(sqlDialect dialect.Dialect,
+	onlyIfChanged bool) (string, []interface{}, error) {
+	if onlyIfChanged && !satInsert.HasHashDiff {
+		return "", nil, fmt.Errorf(
+			"satelite %s has no hash diff column to detect changes", satInsert.SateliteName)
+	}
+
+	if hashErr := satInsert.FillHashKey(nil); hashErr != nil {
+		return "", nil, hashErr
+	}
+
+	sqlDialect = getDialect(sqlDialect)
+	quote := sqlDialect.QuoteIdentifier
+	sql := fmt.Sprintf("UPDATE %s SET %s = ? \nWHERE %s = ? AND %s IS NULL AND %s < ?",
+		quote(satInsert.getDbTableName()),
+		quote(definition.END_DATE),
+		quote(satInsert.getHashKeyDbColumnName()),
+		quote(definition.END_DATE),
+		quote(definition.LOAD_DATE))
+	args := []interface{}{satInsert.LoadDate, satInsert.getHashKeyValue(), satInsert.LoadDate}
+
+	keyConditions, keyArgs, keyErr := satInsert.getMultiActiveConditions(sqlDialect, "")
+	if keyErr != nil {
+		return "", nil, keyErr
+	}
+	sql = sql + keyConditions
+	args = append(args, keyArgs...)
+
+	if onlyIfChanged {
+		sql = sql + fmt.Sprintf(" AND %s <> ?", quote(definition.HASH_DIFF))
+		args = append(args, satInsert.HashDiff)
+	}
+
+	return dialect.Rebind(sqlDialect, sql), args, nil
+}
+
+//getMultiActiveConditions is to generate " AND <column> = ?" condition of each multi-active key
+//with optional column prefix (e.g. table alias) and its ordered argument list
+func (satInsert *SateliteInsertRecord) getMultiActiveConditions(sqlDialect dialect.Dialect,
+	prefix string) (string, []interface{}, error) {
+	conditions := ""
+	args := []interface{}{}
+	for _, key := range satInsert.MultiActiveKeys {
+		var keyAttr *SateliteAttrInsertRecord
+		for index := range satInsert.Attributes {
+			if strings.Compare(stringtool.ToSnakeCase(satInsert.Attributes[index].AttributeName),
+				stringtool.ToSnakeCase(key)) == 0 {
+				keyAttr = &satInsert.Attributes[index]
+				break
+			}
 		}
 
-		if index == 0 {
-			columns = "`" + stringtool.ToSnakeCase(attrValue.AttributeName) + "`"
-			values = tmpStr
-		} else {
-			columns = columns + ",`" + stringtool.ToSnakeCase(attrValue.AttributeName) + "`"
-			values = values + "," + tmpStr
+		if keyAttr == nil || keyAttr.Value == nil {
+			return "", nil, fmt.Errorf("satelite %s has no value for multi-active key %s",
+				satInsert.SateliteName, key)
 		}
+
+		keyArg, keyErr := keyAttr.convertValueToArg()
+		if keyErr != nil {
+			return "", nil, keyErr
+		}
+
+		conditions = conditions + fmt.Sprintf(" AND %s%s = ?",
+			prefix, sqlDialect.QuoteIdentifier(stringtool.ToSnakeCase(key)))
+		args = append(args, keyArg)
+	}
+
+	return conditions, args, nil
+}
+
+//prepareParamInsert to generate insert column list and its ordered argument list
+func (satInsert *SateliteInsertRecord) prepareParamInsert() ([]rdbmstool.ColumnDefinition, []interface{}, error) {
+	if satInsert.Attributes == nil || len(satInsert.Attributes) == 0 {
+		return nil, nil, errors.New(
+			"unable to generate SQL to insert new satelite record as there is no attribute found")
+	}
+
+	if hashErr := satInsert.FillHashKey(nil); hashErr != nil {
+		return nil, nil, hashErr
 	}
 
-	sql := fmt.Sprintf("INSERT INTO `%s` \n(`%s`, `%s`, `%s`, %s) \nVALUES \n(%s, %s, %s, %s)",
-		satInsert.getDbTableName(),
-		satInsert.getHubColumnName(),
-		definition.LOAD_DATE,
-		definition.RECORD_SOURCE,
-		columns,
-		"'"+satInsert.HubHashKeyValue+"'",
-		"'"+satInsert.LoadDate.Format("2006-01-02")+"'",
-		"'"+satInsert.RecordSource+"'",
-		values)
+	cols := []rdbmstool.ColumnDefinition{
+		createHashKeyColumn(satInsert.getHashKeyDbColumnName(), satInsert.getHashKeyValue()),
+		rdbmstool.ColumnDefinition{Name: definition.LOAD_DATE, DataType: rdbmstool.DATETIME},
+		rdbmstool.ColumnDefinition{Name: definition.RECORD_SOURCE,
+			DataType: rdbmstool.CHAR, Length: 100}}
+	args := []interface{}{
+		satInsert.getHashKeyValue(),
+		satInsert.LoadDate,
+		satInsert.RecordSource}
 
-	return sql, nil
+	if satInsert.HasHashDiff {
+		cols = append(cols, createHashKeyColumn(definition.HASH_DIFF, satInsert.HashDiff))
+		args = append(args, satInsert.HashDiff)
+	}
+
+	attrCols, attrArgs, attrErr := prepareAttributeInsert(satInsert.Attributes)
+	if attrErr != nil {
+		return nil, nil, fmt.Errorf(
+			"SateliteInsertRecord Fail to generate SQL: \n%s", attrErr.Error())
+	}
+
+	return append(cols, attrCols...), append(args, attrArgs...), nil
 }
 
-func (attrValue *SateliteAttrInsertRecord) convertValueToString() (string, error) {
+//convertValueToArg validate attribute value against its meta data type and
+//return value which is safe to bind as SQL statement argument
+func (attrValue *SateliteAttrInsertRecord) convertValueToArg() (interface{}, error) {
+	if attrValue.Meta == nil {
+		return nil, fmt.Errorf("attribute %s has no meta definition", attrValue.AttributeName)
+	}
+
 	if attrValue.Value == nil {
-		return "", errors.New("value cannot be null")
+		if attrValue.Meta.IsNullable {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("attribute %s value cannot be null", attrValue.AttributeName)
 	}
 
 	metaType := reflect.TypeOf(attrValue.Value)
 	dataType := attrValue.Meta.DataType
 
 	if dataType == rdbmstool.BOOLEAN && metaType.Kind() == reflect.Bool {
-		tmpBool, _ := attrValue.Value.(bool)
-		if tmpBool {
-			return "true", nil
-		}
-		return "false", nil
-
-	} else if dataType == rdbmstool.DATE && metaType == reflect.TypeOf(time.Time{}) {
-		tmpTime, _ := attrValue.Value.(time.Time)
-		return fmt.Sprintf("'%s'", tmpTime.Format("2006-01-02")), nil
+		return attrValue.Value, nil
 
-	} else if dataType == rdbmstool.DATETIME && metaType == reflect.TypeOf(time.Time{}) {
-		tmpTime, _ := attrValue.Value.(time.Time)
-		return fmt.Sprintf("'%s'", tmpTime.Format("2006-01-02 15:04:05")), nil
+	} else if (dataType == rdbmstool.DATE || dataType == rdbmstool.DATETIME) &&
+		metaType == reflect.TypeOf(time.Time{}) {
+		return attrValue.Value, nil
 
-	} else if dataType == rdbmstool.DECIMAL &&
+	} else if (dataType == rdbmstool.DECIMAL || dataType == rdbmstool.FLOAT) &&
 		(metaType.Kind() == reflect.Float32 || metaType.Kind() == reflect.Float64) {
-		tmpFloat, _ := attrValue.Value.(float64)
-		return fmt.Sprintf("%."+strconv.Itoa(attrValue.Meta.DecimalPrecision)+"f", tmpFloat), nil
-
-	} else if dataType == rdbmstool.FLOAT && metaType.Kind() == reflect.Float32 {
-		tmpFloat, _ := attrValue.Value.(float32)
-		return fmt.Sprintf("%f", tmpFloat), nil
+		return attrValue.Value, nil
 
 	} else if dataType == rdbmstool.INTEGER && metaType.Kind() == reflect.Int {
-		tmpInt, _ := attrValue.Value.(int)
-		return fmt.Sprintf("%d", tmpInt), nil
-
-	} else if dataType == rdbmstool.TEXT && metaType.Kind() == reflect.String {
-		tmpStr, _ := attrValue.Value.(string)
-		return fmt.Sprintf("'%s'", tmpStr), nil
+		return attrValue.Value, nil
 
-	} else if dataType == rdbmstool.CHAR && metaType.Kind() == reflect.String {
-		tmpStr, _ := attrValue.Value.(string)
-		return fmt.Sprintf("'%s'", tmpStr), nil
+	} else if (dataType == rdbmstool.TEXT || dataType == rdbmstool.CHAR ||
+		dataType == rdbmstool.VARCHAR) && metaType.Kind() == reflect.String {
+		return attrValue.Value, nil
 
 	} else {
-		return "", errors.New("value type not match: value type is: " + metaType.Name())
+		return nil, errors.New("value type not match: value type is: " + metaType.Name())
 	}
 }
@@ -0,0 +1,141 @@
+package record
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guinso/datavault/definition"
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+)
+
+func createTestSateliteInsertRecord() SateliteInsertRecord {
+	return SateliteInsertRecord{
+		SateliteName:    "Invoice",
+		Revision:        0,
+		RecordSource:    "erp",
+		HubName:         "Invoice",
+		HubHashKeyValue: "0123456789abcdef0123456789abcdef",
+		LoadDate:        time.Date(2017, 8, 3, 10, 0, 0, 0, time.UTC),
+		HasHashDiff:     true,
+		Attributes: []SateliteAttrInsertRecord{
+			SateliteAttrInsertRecord{
+				AttributeName: "Remark",
+				Value:         "O'Brien's order",
+				Meta: &definition.SateliteAttributeDefinition{
+					Name: "Remark", DataType: rdbmstool.TEXT, IsNullable: true}},
+			SateliteAttrInsertRecord{
+				AttributeName: "Tax",
+				Value:         12.5,
+				Meta: &definition.SateliteAttributeDefinition{
+					Name: "Tax", DataType: rdbmstool.DECIMAL, Length: 10, DecimalPrecision: 2}}}}
+}
+
+func TestSateliteHashDiff(t *testing.T) {
+	satA := createTestSateliteInsertRecord()
+	satB := createTestSateliteInsertRecord()
+	satB.Attributes[0], satB.Attributes[1] = satB.Attributes[1], satB.Attributes[0]
+	satB.LoadDate = satB.LoadDate.AddDate(0, 0, 1)
+
+	if err := satA.FillHashKey(nil); err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if err := satB.FillHashKey(nil); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if satA.HashDiff == "" || strings.Compare(satA.HashDiff, satB.HashDiff) != 0 {
+		t.Errorf("Expect same hash diff for same attribute values, given %s and %s instead",
+			satA.HashDiff, satB.HashDiff)
+	}
+
+	satC := createTestSateliteInsertRecord()
+	satC.Attributes[1].Value = 13.0
+	if err := satC.FillHashKey(nil); err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if strings.Compare(satA.HashDiff, satC.HashDiff) == 0 {
+		t.Error("Expect different hash diff for different attribute values")
+	}
+}
+
+func TestSateliteHashDiffNoNormalisation(t *testing.T) {
+	satA := createTestSateliteInsertRecord()
+	satA.Attributes[0].Value = "acme"
+
+	satB := createTestSateliteInsertRecord()
+	satB.Attributes[0].Value = "ACME"
+
+	satC := createTestSateliteInsertRecord()
+	satC.Attributes[0].Value = "acme "
+
+	satNull := createTestSateliteInsertRecord()
+	satNull.Attributes[0].Value = nil
+
+	satEmpty := createTestSateliteInsertRecord()
+	satEmpty.Attributes[0].Value = ""
+
+	for _, sat := range []*SateliteInsertRecord{&satA, &satB, &satC, &satNull, &satEmpty} {
+		if err := sat.FillHashKey(nil); err != nil {
+			t.Error(err.Error())
+			return
+		}
+	}
+
+	if strings.Compare(satA.HashDiff, satB.HashDiff) == 0 {
+		t.Error("Expect different hash diff for case-only change")
+	}
+
+	if strings.Compare(satA.HashDiff, satC.HashDiff) == 0 {
+		t.Error("Expect different hash diff for trailing space change")
+	}
+
+	if strings.Compare(satNull.HashDiff, satEmpty.HashDiff) == 0 {
+		t.Error("Expect different hash diff for NULL and empty string")
+	}
+}
+
+func TestSateliteGenerateChangedParamSQL(t *testing.T) {
+	sat := createTestSateliteInsertRecord()
+
+	sql, args, err := sat.GenerateChangedParamSQL(dialect.MYSQL)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if strings.Contains(sql, "O'Brien") {
+		t.Errorf("Attribute value should not be embedded into SQL statement: %s", sql)
+	}
+
+	if strings.Count(sql, "?") != len(args) {
+		t.Errorf("Expect %d placeholders, given %d instead", len(args), strings.Count(sql, "?"))
+	}
+
+	if !strings.Contains(sql, "`hash_diff`") {
+		t.Errorf("Expect hash diff column in SQL statement: %s", sql)
+	}
+}
+
+func TestSateliteGenerateChangedParamSQLPostgres(t *testing.T) {
+	sat := createTestSateliteInsertRecord()
+
+	sql, args, err := sat.GenerateChangedParamSQL(dialect.POSTGRES)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+
+	if strings.Contains(sql, "?") || strings.Contains(sql, "`") || strings.Contains(sql, "DUAL") {
+		t.Errorf("Expect PostgreSQL statement, given %s instead", sql)
+	}
+
+	if !strings.Contains(sql, "CAST($6 AS NUMERIC(10,2))") || len(args) != 9 {
+		t.Errorf("Expect casted placeholder for tax attribute, given %s instead", sql)
+	}
+}
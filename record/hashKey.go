@@ -0,0 +1,57 @@
+package record
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/guinso/datavault/hashkey"
+	"github.com/guinso/stringtool"
+)
+
+//computeBusinessHashKey compute hash key from business key values; values are
+//ordered by business key column name so caller's ordering does not affect result
+func computeBusinessHashKey(hasher *hashkey.Hasher, businessKeys []HubBusinessKeyInsertRecord) string {
+	sorted := make([]HubBusinessKeyInsertRecord, len(businessKeys))
+	copy(sorted, businessKeys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return stringtool.ToSnakeCase(sorted[i].BusinessKey) <
+			stringtool.ToSnakeCase(sorted[j].BusinessKey)
+	})
+
+	values := make([]string, len(sorted))
+	for index, business := range sorted {
+		values[index] = business.BusinessValue
+	}
+
+	return getHasher(hasher).HashKey(values...)
+}
+
+func getHasher(hasher *hashkey.Hasher) *hashkey.Hasher {
+	if hasher == nil {
+		return hashkey.CreateHasher()
+	}
+
+	return hasher
+}
+
+//formatHashInput convert attribute value into canonical string before hashing
+func formatHashInput(value interface{}) string {
+	switch tmp := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return tmp
+	case bool:
+		return strconv.FormatBool(tmp)
+	case time.Time:
+		return tmp.Format("2006-01-02 15:04:05")
+	case float32:
+		return strconv.FormatFloat(float64(tmp), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(tmp, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", tmp)
+	}
+}
@@ -0,0 +1,89 @@
+package record
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/guinso/datavault/dialect"
+	"github.com/guinso/rdbmstool"
+	"github.com/guinso/stringtool"
+)
+
+//getDialect is to get SQL dialect of generated statement; MySQL is default dialect
+func getDialect(sqlDialect dialect.Dialect) dialect.Dialect {
+	if sqlDialect == nil {
+		return dialect.MYSQL
+	}
+
+	return sqlDialect
+}
+
+func getColumnNames(cols []rdbmstool.ColumnDefinition) []string {
+	columnNames := make([]string, len(cols))
+	for index, col := range cols {
+		columnNames[index] = col.Name
+	}
+
+	return columnNames
+}
+
+//generateParamInsertSQL is to generate parameterized insert statement with dialect's placeholder
+func generateParamInsertSQL(sqlDialect dialect.Dialect, tableName string, columnNames []string) string {
+	values := make([]string, len(columnNames))
+	for index := range columnNames {
+		values[index] = "?"
+	}
+
+	return dialect.Rebind(sqlDialect, fmt.Sprintf("INSERT INTO %s \n(%s) \nVALUES (%s)",
+		sqlDialect.QuoteIdentifier(tableName),
+		dialect.QuoteIdentifiers(sqlDialect, columnNames),
+		strings.Join(values, ", ")))
+}
+
+//generateLiteralInsertSQL is to generate MySQL insert statement with value literals
+func generateLiteralInsertSQL(tableName string, cols []rdbmstool.ColumnDefinition,
+	values []interface{}) (string, error) {
+	columnNames := make([]string, len(cols))
+	literals := make([]string, len(cols))
+	for index, col := range cols {
+		literal, literalErr := dialect.MYSQL.RenderLiteral(values[index], col)
+		if literalErr != nil {
+			return "", fmt.Errorf("Column %s: %s", col.Name, literalErr.Error())
+		}
+
+		columnNames[index] = col.Name
+		literals[index] = literal
+	}
+
+	return fmt.Sprintf("INSERT INTO %s \n(%s) \nVALUES (%s)",
+		dialect.MYSQL.QuoteIdentifier(tableName),
+		dialect.QuoteIdentifiers(dialect.MYSQL, columnNames),
+		strings.Join(literals, ", ")), nil
+}
+
+//createHashKeyColumn is to create hash key column sized by hash key value, as length
+//of hash key depends on hash algorithm (e.g. 32 for MD5, 64 for SHA-256)
+func createHashKeyColumn(columnName string, hashKey string) rdbmstool.ColumnDefinition {
+	return rdbmstool.ColumnDefinition{Name: columnName, DataType: rdbmstool.CHAR, Length: len(hashKey)}
+}
+
+//prepareAttributeInsert to generate insert column list and its ordered argument list of attribute values
+func prepareAttributeInsert(attrs []SateliteAttrInsertRecord) ([]rdbmstool.ColumnDefinition, []interface{}, error) {
+	cols := []rdbmstool.ColumnDefinition{}
+	args := []interface{}{}
+	for _, attrValue := range attrs {
+		tmpArg, tmpErr := attrValue.convertValueToArg()
+		if tmpErr != nil {
+			return nil, nil, tmpErr
+		}
+
+		cols = append(cols, rdbmstool.ColumnDefinition{
+			Name:             stringtool.ToSnakeCase(attrValue.AttributeName),
+			DataType:         attrValue.Meta.DataType,
+			Length:           attrValue.Meta.Length,
+			DecimalPrecision: attrValue.Meta.DecimalPrecision})
+		args = append(args, tmpArg)
+	}
+
+	return cols, args, nil
+}